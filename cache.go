@@ -0,0 +1,138 @@
+package slopeone
+
+import (
+	"container/list"
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"sync"
+)
+
+// EnablePredictCache caches the predictions made by Predict for the
+// size most recently predicted users, e.g., for power users that
+// repeatedly request recommendations without changing their ratings.
+//
+// Cached predictions are returned only while the model and its
+// settings are unchanged; any training, or change to a setting that
+// affects predictions, invalidates them. Predictions returned from the
+// cache are not passed to the predict observer. A size of 0 or less
+// disables the cache.
+func (s1 *S1) EnablePredictCache(size int) {
+	if size <= 0 {
+		s1.cache = nil
+		return
+	}
+	s1.cache = &predictCache{
+		size:    size,
+		lru:     list.New(),
+		entries: make(map[uint64][]*list.Element),
+	}
+}
+
+// predictCache is a least-recently-used cache of predictions, keyed by
+// the ratings they were predicted from. It is safe for concurrent use.
+type predictCache struct {
+	mu      sync.Mutex
+	size    int
+	gen     uint64
+	lru     *list.List // of *cacheEntry, most recently used first
+	entries map[uint64][]*list.Element
+}
+
+type cacheEntry struct {
+	hash uint64
+	ur   UserRatings
+	p    map[int]float64
+}
+
+// predict returns s1's predictions for ur, from the cache if possible.
+func (c *predictCache) predict(s1 *S1, ur UserRatings) map[int]float64 {
+	h := hashRatings(ur)
+
+	c.mu.Lock()
+	if c.gen != s1.gen {
+		c.lru.Init()
+		c.entries = make(map[uint64][]*list.Element)
+		c.gen = s1.gen
+	}
+	for _, el := range c.entries[h] {
+		if e := el.Value.(*cacheEntry); equalRatings(e.ur, ur) {
+			c.lru.MoveToFront(el)
+			c.mu.Unlock()
+			return copyPredictions(e.p)
+		}
+	}
+	c.mu.Unlock()
+
+	p := s1.predict(ur, false)
+
+	cp := make(UserRatings, len(ur))
+	for i, r := range ur {
+		cp[i] = r
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen != s1.gen {
+		return p
+	}
+	c.entries[h] = append(c.entries[h], c.lru.PushFront(&cacheEntry{h, cp, copyPredictions(p)}))
+	for c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+	return p
+}
+
+// remove removes el from the cache.
+func (c *predictCache) remove(el *list.Element) {
+	e := c.lru.Remove(el).(*cacheEntry)
+	els := c.entries[e.hash]
+	for k := range els {
+		if els[k] == el {
+			els = append(els[:k], els[k+1:]...)
+			break
+		}
+	}
+	if len(els) == 0 {
+		delete(c.entries, e.hash)
+	} else {
+		c.entries[e.hash] = els
+	}
+}
+
+// hashRatings returns a hash of ur that does not depend on the order
+// of its ratings.
+func hashRatings(ur UserRatings) uint64 {
+	var sum uint64
+	var buf [16]byte
+	for i, r := range ur {
+		binary.LittleEndian.PutUint64(buf[0:], uint64(i))
+		binary.LittleEndian.PutUint64(buf[8:], math.Float64bits(r))
+		h := fnv.New64a()
+		h.Write(buf[:])
+		sum += h.Sum64()
+	}
+	return sum
+}
+
+// equalRatings reports whether a and b hold the same ratings.
+func equalRatings(a, b UserRatings) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, r := range a {
+		if rb, ok := b[i]; !ok || math.Float64bits(rb) != math.Float64bits(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// copyPredictions returns a copy of p.
+func copyPredictions(p map[int]float64) map[int]float64 {
+	cp := make(map[int]float64, len(p))
+	for i, r := range p {
+		cp[i] = r
+	}
+	return cp
+}
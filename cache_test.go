@@ -0,0 +1,37 @@
+package slopeone
+
+import "testing"
+
+func TestPredictCache(t *testing.T) {
+	s1 := newFixture()
+	s1.EnablePredictCache(2)
+	var calls int
+	s1.SetPredictObserver(func(int, float64, int) { calls++ })
+
+	ur := UserRatings{2005: 2}
+	first := s1.Predict(ur)
+	n := calls
+	if got := s1.Predict(UserRatings{2005: 2}); calls != n || !samePredictions(got, first) {
+		t.Errorf("cached: got %v after %d predictions, want %v", got, calls-n, first)
+	}
+
+	// The cached predictions are not shared with callers.
+	s1.Predict(ur)[1] = 100
+	if got := s1.Predict(ur)[1]; got == 100 {
+		t.Error("modifying returned predictions modified the cache")
+	}
+
+	s1.AddRatings([]UserRatings{{2005: 1, 1: 5}})
+	if got := s1.Predict(ur); calls == n || samePredictions(got, first) {
+		t.Errorf("after AddRatings: got cached %v", got)
+	}
+
+	// Filling the cache with two other users evicts ur.
+	s1.Predict(UserRatings{1: 1})
+	s1.Predict(UserRatings{5513: 1})
+	n = calls
+	s1.Predict(ur)
+	if calls == n {
+		t.Error("evicted predictions were served from the cache")
+	}
+}
@@ -0,0 +1,52 @@
+package slopeone
+
+import "math"
+
+// CleanReport describes the changes Clean made to a set of user
+// ratings.
+type CleanReport struct {
+	// DroppedUsers is the number of users removed for having no
+	// ratings, including those left with none after removing NaNs.
+	DroppedUsers int
+
+	// ClampedRatings is the number of ratings outside of the rating
+	// scale that were clamped to it.
+	ClampedRatings int
+
+	// RemovedNaNs is the number of NaN ratings removed.
+	RemovedNaNs int
+}
+
+// Clean returns a copy of data with obviously bad ratings fixed, ready
+// to be added to an S1, along with a report of what was changed.
+//
+// NaN ratings are removed, ratings outside of the scale [min, max] are
+// clamped to it, and users with no ratings are dropped. data is not
+// modified.
+func Clean(data []UserRatings, min, max float64) (cleaned []UserRatings, report CleanReport) {
+	cleaned = make([]UserRatings, 0, len(data))
+	for _, ur := range data {
+		cu := make(UserRatings, len(ur))
+		for i, r := range ur {
+			switch {
+			case math.IsNaN(r):
+				report.RemovedNaNs++
+				continue
+			case r < min:
+				r = min
+				report.ClampedRatings++
+			case r > max:
+				r = max
+				report.ClampedRatings++
+			}
+			cu[i] = r
+		}
+
+		if len(cu) == 0 {
+			report.DroppedUsers++
+			continue
+		}
+		cleaned = append(cleaned, cu)
+	}
+	return cleaned, report
+}
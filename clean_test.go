@@ -0,0 +1,54 @@
+package slopeone
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   []UserRatings
+		want   []UserRatings
+		report CleanReport
+	}{
+		{
+			name:   "empty users",
+			data:   []UserRatings{{}, nil, {1: 3}},
+			want:   []UserRatings{{1: 3}},
+			report: CleanReport{DroppedUsers: 2},
+		},
+		{
+			name:   "out of scale",
+			data:   []UserRatings{{1: 7, 2: -1, 3: math.Inf(1), 4: 2}},
+			want:   []UserRatings{{1: 5, 2: 0, 3: 5, 4: 2}},
+			report: CleanReport{ClampedRatings: 3},
+		},
+		{
+			name:   "NaNs",
+			data:   []UserRatings{{1: math.NaN(), 2: 4}, {1: math.NaN()}},
+			want:   []UserRatings{{2: 4}},
+			report: CleanReport{DroppedUsers: 1, RemovedNaNs: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, report := Clean(tt.data, 0, 5)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if report != tt.report {
+				t.Errorf("got report %+v, want %+v", report, tt.report)
+			}
+		})
+	}
+}
+
+func TestCleanDoesNotModify(t *testing.T) {
+	data := []UserRatings{{1: 7, 2: math.NaN()}}
+	Clean(data, 0, 5)
+	if data[0][1] != 7 || len(data[0]) != 2 {
+		t.Errorf("data was modified: %v", data)
+	}
+}
@@ -0,0 +1,90 @@
+package slopeone
+
+import "math"
+
+// Prediction is a predicted rating along with the support for it.
+type Prediction struct {
+	Rating float64
+
+	// Support is the number of co-ratings between the item and the
+	// user's rated items that the prediction is based on. Predictions
+	// with more support can be trusted more.
+	Support int
+}
+
+// PredictWithConfidence is like Predict, but also returns the support
+// for each prediction, and the number of the user's rated items that
+// are unknown to the model, e.g., because they were newly launched.
+// Unknown items cannot contribute to predictions, so a high number
+// indicates that there is not yet enough data on the user's ratings.
+func (s1 *S1) PredictWithConfidence(ur UserRatings) (map[int]Prediction, int) {
+	var unknown int
+	for i := range ur {
+		if _, ok := s1.d[i]; !ok {
+			unknown++
+		}
+	}
+
+	est := s1.estimates(ur, false)
+	p := make(map[int]Prediction, len(est))
+	for i, e := range est {
+		if r, ok := s1.finish(i, e); ok {
+			p[i] = Prediction{Rating: r, Support: e.support}
+		}
+	}
+	return p, unknown
+}
+
+// PredictionStdErr is a predicted rating along with an estimate of its
+// standard error.
+type PredictionStdErr struct {
+	Rating float64
+	StdErr float64
+}
+
+// PredictWithStdErr is like Predict, but also estimates the standard
+// error of each prediction, so that predictions backed by more, and
+// more consistent, co-ratings report lower errors.
+//
+// A prediction is the weighted average of the predictions made from
+// each of the user's rated items i, each of which adds the average of
+// the n_i rating differences observed between the pair of items. If
+// those differences have variance v_i, the average has variance
+// v_i / n_i, so the prediction, with weights w_i, has a standard error
+// of
+//
+//	sqrt(sum(w_i^2 * v_i / n_i)) / sum(w_i)
+//
+// taking the pairs' averages to be independent. v_i is the pair's
+// variance, from PairVariance, if variance weighting is enabled, and
+// is otherwise taken to be 1, so that the error reflects only the
+// support of the prediction and is in units of the differences'
+// standard deviation. The error is in the units the model calculates
+// in, e.g., log-odds in log-odds mode, and does not account for
+// clamping, rounding or shrinkage of the prediction.
+func (s1 *S1) PredictWithStdErr(ur UserRatings) map[int]PredictionStdErr {
+	sd := s1.spread(ur)
+	est := s1.estimates(ur, false)
+	p := make(map[int]PredictionStdErr, len(est))
+	for gi, e := range est {
+		r, ok := s1.finish(gi, e)
+		if !ok {
+			continue
+		}
+
+		var sq float64
+		for i, ri := range ur {
+			var c estimate
+			if s1.contribute(&c, gi, i, ri, sd); c.weight == 0 {
+				continue
+			}
+			v := 1.0
+			if s1.sq != nil {
+				v, _ = s1.PairVariance(gi, i)
+			}
+			sq += c.weight * c.weight * v * sd * sd / float64(c.support)
+		}
+		p[gi] = PredictionStdErr{Rating: r, StdErr: math.Sqrt(sq) / e.weight}
+	}
+	return p
+}
@@ -0,0 +1,54 @@
+package slopeone
+
+import "testing"
+
+func TestPredictWithConfidence(t *testing.T) {
+	s1 := newFixture()
+	ur := UserRatings{2005: 2.0, 29074: 3.2, 77: 1, 78: 2}
+
+	got, unknown := s1.PredictWithConfidence(ur)
+	if unknown != 2 {
+		t.Errorf("got %d unknown items, want 2", unknown)
+	}
+	want := s1.Predict(ur)
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i, r := range want {
+		if !near(got[i].Rating, r) {
+			t.Errorf("item %d: got %v, want %v", i, got[i].Rating, r)
+		}
+	}
+
+	// Item 359602 is co-rated with 29074 twice, and 2005 once.
+	if got := got[359602].Support; got != 3 {
+		t.Errorf("got support %d, want 3", got)
+	}
+}
+
+func TestPredictWithStdErr(t *testing.T) {
+	var data []UserRatings
+	for k := 0; k < 16; k++ {
+		data = append(data, UserRatings{1: 3, 2: 4})
+	}
+	data = append(data, UserRatings{1: 3, 3: 4})
+	s1 := NewS1()
+	s1.AddRatings(data)
+
+	// Without variance weighting the differences are taken to have a
+	// variance of 1, so the standard error is 1 / sqrt(support).
+	got := s1.PredictWithStdErr(UserRatings{1: 3})
+	if !near(got[2].Rating, 4) || !near(got[2].StdErr, 0.25) || !near(got[3].StdErr, 1) {
+		t.Errorf("got %+v, want standard errors of 0.25 and 1", got)
+	}
+
+	// Item 2 is rated 1 and 3 above item 1, a variance of 1 over 2
+	// co-ratings; item 3 is always rated 1 above it.
+	s1 = NewS1()
+	s1.SetVarianceWeighting(true)
+	s1.AddRatings([]UserRatings{{1: 3, 2: 4}, {1: 3, 2: 6}, {1: 1, 3: 2}, {1: 1, 3: 2}})
+	got = s1.PredictWithStdErr(UserRatings{1: 3})
+	if !near(got[2].StdErr*got[2].StdErr, 0.5) || got[3].StdErr != 0 {
+		t.Errorf("variance weighted: got %+v, want standard errors of sqrt(0.5) and 0", got)
+	}
+}
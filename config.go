@@ -0,0 +1,80 @@
+package slopeone
+
+import "fmt"
+
+// Config describes how an S1 is trained: the settings that must be
+// chosen before any ratings are added, as they determine what the S1
+// stores about each pair of items.
+type Config struct {
+	// LogOdds is true in log-odds mode, set with SetLogOdds.
+	LogOdds bool
+
+	// Normalization is how each user's ratings are normalised, set
+	// with SetNormalization.
+	Normalization Normalization
+
+	// VarianceWeighting is true if pairs are weighted by the variance
+	// of their differences, set with SetVarianceWeighting.
+	VarianceWeighting bool
+
+	// TrimFraction is the fraction of each pair's differences trimmed
+	// from either end, set with SetTrimFraction.
+	TrimFraction float64
+
+	// EMAAlpha is the weight of each new difference in its pair's
+	// moving average, set with SetEMAAlpha, or 0 for plain averages.
+	EMAAlpha float64
+
+	// MaxItemsPerUser is the number of ratings beyond which a user's
+	// influence is capped, set with SetMaxItemsPerUser, or 0.
+	MaxItemsPerUser int
+}
+
+// Config returns the settings the S1 is trained with, e.g., to check
+// them with Validate.
+func (s1 *S1) Config() Config {
+	return Config{
+		LogOdds:           s1.logOdds,
+		Normalization:     s1.normalization,
+		VarianceWeighting: s1.sq != nil,
+		TrimFraction:      s1.trim,
+		EMAAlpha:          s1.emaAlpha,
+		MaxItemsPerUser:   s1.maxItemsPerUser,
+	}
+}
+
+// Validate returns an error wrapping ErrIncompatibleConfig if c
+// combines settings that cannot be used together:
+//
+//   - A trimmed mean is computed from a pair's retained differences,
+//     so it cannot be a moving average as well.
+//   - The variance of a pair's differences is measured about their
+//     plain average, so it is meaningless about a moving average.
+//
+// Every other combination is well defined. In particular, ratings are
+// normalised before each difference updates its pair's moving average.
+func (c Config) Validate() error {
+	if c.EMAAlpha > 0 && c.TrimFraction > 0 {
+		return fmt.Errorf("%w: trimming cannot be combined with a moving average", ErrIncompatibleConfig)
+	}
+	if c.EMAAlpha > 0 && c.VarianceWeighting {
+		return fmt.Errorf("%w: variance weighting cannot be combined with a moving average", ErrIncompatibleConfig)
+	}
+	return nil
+}
+
+// NewS1WithConfig returns an *S1 ready for use, trained with the
+// settings in c, or an error if c is not valid.
+func NewS1WithConfig(c Config) (*S1, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	s1 := NewS1()
+	s1.SetLogOdds(c.LogOdds)
+	s1.SetNormalization(c.Normalization)
+	s1.SetVarianceWeighting(c.VarianceWeighting)
+	s1.SetTrimFraction(c.TrimFraction)
+	s1.SetEMAAlpha(c.EMAAlpha)
+	s1.SetMaxItemsPerUser(c.MaxItemsPerUser)
+	return s1, nil
+}
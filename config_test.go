@@ -0,0 +1,51 @@
+package slopeone
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewS1WithConfig(t *testing.T) {
+	c := Config{Normalization: ZScore, EMAAlpha: 0.5}
+	s1, err := NewS1WithConfig(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s1.Config(); got != c {
+		t.Errorf("got %+v, want %+v", got, c)
+	}
+
+	// Each user's z-scores put item 1 2 above item 2, then 2 below it,
+	// then 2 above it again, so the moving average goes from 2 to 0 to
+	// 1. A user with a single rating has a standard deviation of 1.
+	for _, ur := range []UserRatings{{1: 5, 2: 1}, {1: 2, 2: 4}, {1: 4, 2: 3}} {
+		s1.AddRatings([]UserRatings{ur})
+	}
+	if got := s1.Predict(UserRatings{1: 3})[2]; !near(got, 2) {
+		t.Errorf("got %v, want 2", got)
+	}
+
+	// With mean centering, the moving average is as without
+	// normalization.
+	centred, _ := NewS1WithConfig(Config{Normalization: MeanCentering, EMAAlpha: 0.5})
+	plain, _ := NewS1WithConfig(Config{EMAAlpha: 0.5})
+	for _, s := range []*S1{centred, plain} {
+		for _, ur := range fixture {
+			s.AddRatings([]UserRatings{ur})
+		}
+	}
+	if got, want := centred.Predict(fixture[0]), plain.Predict(fixture[0]); !samePredictions(got, want) {
+		t.Errorf("mean centred: got %v, want %v", got, want)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	for _, c := range []Config{
+		{EMAAlpha: 0.5, TrimFraction: 0.1},
+		{EMAAlpha: 0.5, VarianceWeighting: true},
+	} {
+		if _, err := NewS1WithConfig(c); !errors.Is(err, ErrIncompatibleConfig) {
+			t.Errorf("%+v: got %v, want %v", c, err, ErrIncompatibleConfig)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package slopeone
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// LoadCSV reads ratings from r, in CSV format with one rating per
+// record:
+//
+//	user,item,rating
+//
+// where user and item are integers. A header record is permitted as the
+// first record. Records are grouped into one UserRatings per user, in
+// the order each user first appears; if a user rated an item more than
+// once the last rating is used.
+//
+// If r holds gzip-compressed data, it is decompressed transparently.
+func LoadCSV(r io.Reader) ([]UserRatings, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	} else {
+		r = br
+	}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 3
+	cr.ReuseRecord = true
+
+	var users []UserRatings
+	idx := make(map[int]int)
+	for first := true; ; first = false {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+
+		user, err := strconv.Atoi(rec[0])
+		if err != nil && first {
+			continue // header
+		}
+		if err != nil {
+			return nil, fmt.Errorf("slopeone: line %d: invalid user: %w", line, err)
+		}
+		item, err := strconv.Atoi(rec[1])
+		if err != nil {
+			return nil, fmt.Errorf("slopeone: line %d: invalid item: %w", line, err)
+		}
+		rating, err := strconv.ParseFloat(rec[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("slopeone: line %d: invalid rating: %w", line, err)
+		}
+
+		k, ok := idx[user]
+		if !ok {
+			k = len(users)
+			idx[user] = k
+			users = append(users, make(UserRatings))
+		}
+		users[k][item] = rating
+	}
+	return users, nil
+}
+
+// WriteRecommendationsCSV writes recs, which maps user ids to their
+// recommendations, e.g., as returned by TopNForAll, to w in CSV format
+// with one recommendation per record:
+//
+//	user,rank,item,rating
+//
+// preceded by that header record. rank is the position of the
+// recommendation in the user's recommendations, from 1. Records are
+// ordered by ascending user, and then by rank, so the same
+// recommendations are always written identically. Ratings are written
+// with the fewest digits that read back as the same value.
+func WriteRecommendationsCSV(w io.Writer, recs map[int][]Recommendation) error {
+	users := make([]int, 0, len(recs))
+	for u := range recs {
+		users = append(users, u)
+	}
+	sort.Ints(users)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"user", "rank", "item", "rating"}); err != nil {
+		return err
+	}
+	rec := make([]string, 4)
+	for _, u := range users {
+		for k, r := range recs[u] {
+			rec[0] = strconv.Itoa(u)
+			rec[1] = strconv.Itoa(k + 1)
+			rec[2] = strconv.Itoa(r.Item)
+			rec[3] = strconv.FormatFloat(r.Rating, 'g', -1, 64)
+			if err := cw.Write(rec); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
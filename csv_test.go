@@ -0,0 +1,70 @@
+package slopeone
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadCSV(t *testing.T) {
+	const data = "user,item,rating\n1,10,4\n2,10,3\n1,11,2.5\n"
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte(data))
+	zw.Close()
+
+	for name, r := range map[string]io.Reader{
+		"plain":   strings.NewReader(data),
+		"gzipped": &gz,
+	} {
+		users, err := LoadCSV(r)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if len(users) != 2 || len(users[0]) != 2 || users[0][10] != 4 ||
+			users[0][11] != 2.5 || len(users[1]) != 1 || users[1][10] != 3 {
+			t.Errorf("%s: got %v", name, users)
+		}
+	}
+}
+
+func TestLoadCSVError(t *testing.T) {
+	_, err := LoadCSV(strings.NewReader("1,2,3\n1,x,3\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("got %v, want an error on line 2", err)
+	}
+}
+
+func TestWriteRecommendationsCSV(t *testing.T) {
+	tenth := 0.1
+	recs := map[int][]Recommendation{
+		7: {{Item: 3, Rating: 4.5}, {Item: 1, Rating: tenth + 0.2}},
+		2: {{Item: 9, Rating: 5}},
+		5: nil,
+	}
+	var buf bytes.Buffer
+	if err := WriteRecommendationsCSV(&buf, recs); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 0.1 + 0.2 is not 0.3 in floating point, so it is written in full
+	// to read back as the same rating.
+	want := [][]string{
+		{"user", "rank", "item", "rating"},
+		{"2", "1", "9", "5"},
+		{"7", "1", "3", "4.5"},
+		{"7", "2", "1", "0.30000000000000004"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("got %q, want %q", rows, want)
+	}
+}
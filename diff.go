@@ -0,0 +1,164 @@
+package slopeone
+
+import (
+	"math"
+	"sort"
+)
+
+// PairChange describes how the relationship between a pair of items
+// differs between two models.
+type PairChange struct {
+	Item1, Item2 int
+
+	// OldDeviation and NewDeviation are the average difference between
+	// the ratings of Item1 and Item2 in each model, or NaN if the pair
+	// is not in the model.
+	OldDeviation, NewDeviation float64
+
+	// OldSupport and NewSupport are the number of times the pair has
+	// been rated together in each model.
+	OldSupport, NewSupport int
+}
+
+// magnitude returns the absolute change in the pair's deviation, which
+// is infinite if the pair is only in one of the models.
+func (pc PairChange) magnitude() float64 {
+	if math.IsNaN(pc.OldDeviation) || math.IsNaN(pc.NewDeviation) {
+		return math.Inf(1)
+	}
+	return math.Abs(pc.NewDeviation - pc.OldDeviation)
+}
+
+// DiffModels returns the pairs of items whose relationship differs
+// between the old and new models, e.g., to detect drift or data issues
+// after retraining.
+//
+// A pair is returned if its average rating difference or its support
+// changed by more than threshold, or if it is only in one of the
+// models. Each pair is returned once, with Item1 < Item2. Pairs are
+// sorted by descending magnitude of change in the average rating
+// difference, with pairs in only one model first, and then by
+// descending change in support.
+func DiffModels(old, new *S1, threshold float64) []PairChange {
+	var changes []PairChange
+	add := func(i1, i2 int) {
+		pc := PairChange{Item1: i1, Item2: i2, OldDeviation: math.NaN(), NewDeviation: math.NaN()}
+		if dev, ok := old.deviation(i1, i2); ok {
+			pc.OldDeviation, pc.OldSupport = dev, old.f[i1][i2]
+		}
+		if dev, ok := new.deviation(i1, i2); ok {
+			pc.NewDeviation, pc.NewSupport = dev, new.f[i1][i2]
+		}
+		if pc.magnitude() > threshold || math.Abs(float64(pc.NewSupport-pc.OldSupport)) > threshold {
+			changes = append(changes, pc)
+		}
+	}
+
+	for i1, freqs := range old.f {
+		for i2 := range freqs {
+			if i1 < i2 {
+				add(i1, i2)
+			}
+		}
+	}
+	for i1, freqs := range new.f {
+		for i2 := range freqs {
+			if _, ok := old.f[i1][i2]; !ok && i1 < i2 {
+				add(i1, i2)
+			}
+		}
+	}
+
+	abs := func(n int) int {
+		if n < 0 {
+			return -n
+		}
+		return n
+	}
+	sort.Slice(changes, func(a, b int) bool {
+		ca, cb := changes[a], changes[b]
+		if ma, mb := ca.magnitude(), cb.magnitude(); ma != mb {
+			return ma > mb
+		}
+		if sa, sb := abs(ca.NewSupport-ca.OldSupport), abs(cb.NewSupport-cb.OldSupport); sa != sb {
+			return sa > sb
+		}
+		if ca.Item1 != cb.Item1 {
+			return ca.Item1 < cb.Item1
+		}
+		return ca.Item2 < cb.Item2
+	})
+	return changes
+}
+
+// CompareTopN returns, for each of the users in profiles, which maps
+// user ids to their ratings, the overlap between the n highest
+// predicted ratings made by old and by new, e.g., to detect retraining
+// unexpectedly changing users' recommendations.
+//
+// Overlap is the Jaccard index of the two sets of recommended items:
+// the number of items recommended by both models divided by the number
+// recommended by either. It is 1 when both models recommend the same
+// items, in any order, and 0 when they have no items in common. A user
+// for whom neither model can recommend anything has an overlap of 1.
+func CompareTopN(old, new *S1, profiles map[int]UserRatings, n int) map[int]float64 {
+	oldTop := old.TopNForAll(profiles, n)
+	newTop := new.TopNForAll(profiles, n)
+
+	overlap := make(map[int]float64, len(profiles))
+	for user := range profiles {
+		items := make(map[int]bool, len(oldTop[user]))
+		for _, rec := range oldTop[user] {
+			items[rec.Item] = true
+		}
+
+		var both int
+		for _, rec := range newTop[user] {
+			if items[rec.Item] {
+				both++
+			}
+		}
+		either := len(oldTop[user]) + len(newTop[user]) - both
+		if either == 0 {
+			overlap[user] = 1
+			continue
+		}
+		overlap[user] = float64(both) / float64(either)
+	}
+	return overlap
+}
+
+// RecommendationChurn returns the fraction of the n highest predicted
+// ratings, as returned by TopN, that change when a user's ratings
+// change from oldProfile to newProfile, e.g., to decide whether to
+// notify them of new recommendations.
+//
+// Churn is the number of recommendations in the longer of the two
+// lists, less the number of items recommended from both profiles,
+// divided by the number in the longer list, so it is 0 when the same
+// items are recommended, in any order, and 1 when none are recommended
+// again. Recommendations lost because newProfile's list is shorter
+// count towards churn. A user who can be recommended nothing from
+// either profile has a churn of 0.
+func (s1 *S1) RecommendationChurn(oldProfile, newProfile UserRatings, n int) float64 {
+	oldTop, newTop := s1.TopN(oldProfile, n), s1.TopN(newProfile, n)
+	longest := len(oldTop)
+	if len(newTop) > longest {
+		longest = len(newTop)
+	}
+	if longest == 0 {
+		return 0
+	}
+
+	items := make(map[int]bool, len(oldTop))
+	for _, rec := range oldTop {
+		items[rec.Item] = true
+	}
+	var kept int
+	for _, rec := range newTop {
+		if items[rec.Item] {
+			kept++
+		}
+	}
+	return float64(longest-kept) / float64(longest)
+}
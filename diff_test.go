@@ -0,0 +1,76 @@
+package slopeone
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDiffModels(t *testing.T) {
+	old, new := newFixture(), newFixture()
+	new.AddRatings([]UserRatings{{1: 5, 2: 1}, {50: 1, 51: 2}})
+
+	got := DiffModels(old, new, 0.01)
+	if len(got) != 2 {
+		t.Fatalf("got %v, want 2 changes", got)
+	}
+
+	// Pair (50, 51) is only in the new model, so it comes first.
+	if pc := got[0]; pc.Item1 != 50 || pc.Item2 != 51 || !math.IsNaN(pc.OldDeviation) ||
+		!near(pc.NewDeviation, -1) || pc.OldSupport != 0 || pc.NewSupport != 1 {
+		t.Errorf("got %+v, want new pair (50, 51)", pc)
+	}
+	// Pair (1, 2) changed from -1 to (-1 + 4) / 2.
+	if pc := got[1]; pc.Item1 != 1 || pc.Item2 != 2 || !near(pc.OldDeviation, -1) ||
+		!near(pc.NewDeviation, 1.5) || pc.OldSupport != 1 || pc.NewSupport != 2 {
+		t.Errorf("got %+v, want changed pair (1, 2)", pc)
+	}
+
+	if got := DiffModels(old, newFixture(), 0); len(got) != 0 {
+		t.Errorf("identical models: got %v, want no changes", got)
+	}
+}
+
+func TestCompareTopN(t *testing.T) {
+	old := NewS1()
+	old.AddRatings([]UserRatings{{1: 5, 2: 5, 3: 4, 4: 1}})
+	new := NewS1()
+	new.AddRatings([]UserRatings{{1: 5, 2: 1, 3: 4, 4: 5}})
+
+	// User 7's top 2 are items 2 and 3 in the old model, and 4 and 3
+	// in the new. Nothing can be recommended to user 8.
+	profiles := map[int]UserRatings{7: {1: 3}, 8: {9: 1}}
+	got := CompareTopN(old, new, profiles, 2)
+	if !near(got[7], 1.0/3) || got[8] != 1 || len(got) != 2 {
+		t.Errorf("got %v, want 7: 1/3 and 8: 1", got)
+	}
+
+	// A huge n is no different from one covering every item.
+	if got := CompareTopN(old, new, profiles, math.MaxInt); !near(got[7], 1) {
+		t.Errorf("huge n: got %v, want 7: 1", got)
+	}
+}
+
+func TestRecommendationChurn(t *testing.T) {
+	s1 := newFixture()
+	old := UserRatings{2005: 3}
+	if got := s1.RecommendationChurn(old, old, 2); got != 0 {
+		t.Errorf("unchanged: got %v, want 0", got)
+	}
+	if got := s1.RecommendationChurn(old, UserRatings{2005: 3, 1: 5}, 2); got <= 0 {
+		t.Errorf("got %v, want the new rating to change the recommendations", got)
+	}
+	if got := s1.RecommendationChurn(UserRatings{}, UserRatings{}, 3); got != 0 {
+		t.Errorf("nothing recommended: got %v, want 0", got)
+	}
+	if got := s1.RecommendationChurn(UserRatings{}, old, 3); got != 1 {
+		t.Errorf("all new: got %v, want 1", got)
+	}
+
+	// Rating item 2 leaves only item 3 to recommend, so one of the two
+	// earlier recommendations is lost.
+	s1 = NewS1()
+	s1.AddRatings([]UserRatings{{1: 1, 2: 2, 3: 3}})
+	if got := s1.RecommendationChurn(UserRatings{1: 1}, UserRatings{1: 1, 2: 2}, 3); !near(got, 0.5) {
+		t.Errorf("shorter: got %v, want 0.5", got)
+	}
+}
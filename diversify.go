@@ -0,0 +1,200 @@
+package slopeone
+
+import (
+	"math"
+	"sort"
+)
+
+// DiversifyTopN is like TopN, but trades predicted rating for variety,
+// so that the recommendations are not all near-duplicates of one
+// another.
+//
+// Recommendations are chosen greedily, in the manner of maximal
+// marginal relevance: each is the remaining item with the highest
+//
+//	rating - lambda * similarity
+//
+// where similarity is the item's highest similarity, as described by
+// MostSimilar, to any item already chosen, so an item gives up less
+// than lambda, in the units of the rating scale, for being redundant.
+// A lambda of 0 gives the same recommendations as TopN; larger values
+// favour items unlike those already chosen. Ties are broken as in TopN.
+//
+// The recommendations are returned in the order they were chosen, with
+// their predicted ratings.
+func (s1 *S1) DiversifyTopN(ur UserRatings, n int, lambda float64) []Recommendation {
+	if n <= 0 {
+		return nil
+	}
+
+	var candidates []Recommendation
+	for i, e := range s1.estimates(ur, false) {
+		if r, ok := s1.finish(i, e); ok {
+			candidates = append(candidates, Recommendation{Item: i, Rating: r, Support: e.support})
+		}
+	}
+	sortRecommendations(candidates)
+
+	// redundancy holds each candidate's highest similarity to a chosen
+	// item.
+	redundancy := make([]float64, len(candidates))
+	var out []Recommendation
+	for len(out) < n && len(candidates) > 0 {
+		best, bestScore := 0, math.Inf(-1)
+		for k, c := range candidates {
+			// Candidates are sorted, so the first of equal scores
+			// ranks ahead of the rest.
+			if score := c.Rating - lambda*redundancy[k]; score > bestScore {
+				best, bestScore = k, score
+			}
+		}
+
+		chosen := candidates[best]
+		out = append(out, chosen)
+		candidates = append(candidates[:best], candidates[best+1:]...)
+		redundancy = append(redundancy[:best], redundancy[best+1:]...)
+		for k, c := range candidates {
+			redundancy[k] = math.Max(redundancy[k], s1.similarity(c.Item, chosen.Item))
+		}
+	}
+	return out
+}
+
+// IntraListSimilarity returns the average similarity, as used by
+// MostSimilar, between every pair of distinct items in recs, for
+// monitoring how varied a list of recommendations is: the lower the
+// score, the more diverse the list. Lists of fewer than two distinct
+// items have a similarity of 0.
+func (s1 *S1) IntraListSimilarity(recs []Recommendation) float64 {
+	items := make([]int, 0, len(recs))
+	seen := make(map[int]bool, len(recs))
+	for _, r := range recs {
+		if !seen[r.Item] {
+			seen[r.Item] = true
+			items = append(items, r.Item)
+		}
+	}
+	if len(items) < 2 {
+		return 0
+	}
+
+	var sum float64
+	for a := range items {
+		for b := a + 1; b < len(items); b++ {
+			sum += s1.similarity(items[a], items[b])
+		}
+	}
+	return sum / float64(len(items)*(len(items)-1)/2)
+}
+
+// SerendipitousTopN is like TopN, but favours recommendations that are
+// unexpected given the items the user rated highly, i.e., at least
+// their mean rating, over those so obviously related to them that the
+// user is likely to have found them anyway.
+//
+// Each candidate item is ranked by
+//
+//	rating - novelty * obviousness
+//
+// where obviousness is the item's highest similarity, as described by
+// MostSimilar, to any of the user's highly rated items, so items that
+// are predicted to be liked but are only loosely related to the user's
+// favourites rise above near-duplicates of them. A novelty of 0 gives
+// the same recommendations as TopN. Ties are broken as in TopN.
+//
+// The recommendations are returned in ranked order, with their
+// predicted ratings.
+func (s1 *S1) SerendipitousTopN(ur UserRatings, n int, novelty float64) []Recommendation {
+	if n <= 0 {
+		return nil
+	}
+
+	var mean float64
+	for _, r := range ur {
+		mean += r
+	}
+	mean /= float64(len(ur))
+	var liked []int
+	for i, r := range ur {
+		if r >= mean {
+			liked = append(liked, i)
+		}
+	}
+
+	type candidate struct {
+		Recommendation
+		score float64
+	}
+	var candidates []candidate
+	for i, e := range s1.estimates(ur, false) {
+		r, ok := s1.finish(i, e)
+		if !ok {
+			continue
+		}
+		var obviousness float64
+		for _, j := range liked {
+			obviousness = math.Max(obviousness, s1.similarity(i, j))
+		}
+		candidates = append(candidates, candidate{
+			Recommendation: Recommendation{Item: i, Rating: r, Support: e.support},
+			score:          r - novelty*obviousness,
+		})
+	}
+
+	sort.Slice(candidates, func(a, b int) bool {
+		if candidates[a].score != candidates[b].score {
+			return candidates[a].score > candidates[b].score
+		}
+		return ranksBefore(candidates[a].Recommendation, candidates[b].Recommendation)
+	})
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	out := make([]Recommendation, len(candidates))
+	for k, c := range candidates {
+		out[k] = c.Recommendation
+	}
+	return out
+}
+
+// GiniOfRecommendations returns the Gini coefficient of how often each
+// item is recommended across allRecs, which maps user ids to their
+// recommendations, e.g., as returned by TopNForAll. It is 0 when every
+// recommended item is recommended equally often, and approaches 1 as
+// the recommendations concentrate on a few items, so a high value
+// signals that users are being shown the same, typically popular,
+// items.
+//
+// Only items recommended at least once are counted, as the items that
+// could have been recommended are not known. It is 0 if there are no
+// recommendations.
+func GiniOfRecommendations(allRecs map[int][]Recommendation) float64 {
+	freqs := make(map[int]int)
+	for _, recs := range allRecs {
+		for _, r := range recs {
+			freqs[r.Item]++
+		}
+	}
+	if len(freqs) == 0 {
+		return 0
+	}
+
+	counts := make([]int, 0, len(freqs))
+	for _, n := range freqs {
+		counts = append(counts, n)
+	}
+	sort.Ints(counts)
+
+	// With the counts in ascending order, the coefficient is
+	//
+	//	2 * sum(k * counts[k-1]) / (n * sum(counts)) - (n + 1) / n
+	//
+	// for k from 1 to the number of items, n.
+	var weighted, total float64
+	for k, c := range counts {
+		weighted += float64(k+1) * float64(c)
+		total += float64(c)
+	}
+	n := float64(len(counts))
+	return 2*weighted/(n*total) - (n+1)/n
+}
@@ -0,0 +1,126 @@
+package slopeone
+
+import "testing"
+
+// newEchoFixture returns an *S1 in which items 2 and 3 are always rated
+// alike, and item 4, rated a little lower than them, is unrelated to
+// both.
+func newEchoFixture() *S1 {
+	var users []UserRatings
+	for k := 0; k < 10; k++ {
+		users = append(users, UserRatings{1: 3, 2: 5, 3: 4.9})
+	}
+	users = append(users, UserRatings{1: 3, 4: 4.5}, UserRatings{2: 1, 4: 4})
+	s1 := NewS1()
+	s1.AddRatings(users)
+	return s1
+}
+
+func TestDiversifyTopN(t *testing.T) {
+	s1 := newEchoFixture()
+	ur := UserRatings{1: 3}
+
+	top := s1.TopN(ur, 2)
+	if got := s1.DiversifyTopN(ur, 2, 0); len(got) != 2 || got[0] != top[0] || got[1] != top[1] {
+		t.Errorf("lambda 0: got %v, want TopN's %v", got, top)
+	}
+
+	// The higher lambda, the more spread out the recommendations.
+	first := s1.IntraListSimilarity(top)
+	prev := first
+	for _, lambda := range []float64{0, 0.5, 2, 10} {
+		got := s1.DiversifyTopN(ur, 2, lambda)
+		ils := s1.IntraListSimilarity(got)
+		if ils > prev {
+			t.Errorf("lambda %v: got similarity %v, more than %v for a lower lambda", lambda, ils, prev)
+		}
+		prev = ils
+	}
+	if prev >= first {
+		t.Errorf("got similarity %v for a high lambda, want less than TopN's %v", prev, first)
+	}
+	if got := s1.DiversifyTopN(ur, 2, 2); got[0].Item != top[0].Item || got[1].Item != 4 {
+		t.Errorf("lambda 2: got %v, want %d then the unrelated item 4", got, top[0].Item)
+	}
+}
+
+func TestIntraListSimilarity(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{
+		{1: 4, 2: 4, 3: 1},
+		{1: 5, 2: 5, 3: 1},
+		{1: 3, 2: 3},
+	})
+
+	// Items 1 and 2 are always rated alike, three times: a similarity
+	// of 3/4. Item 3 is rated well below item 1.
+	homogeneous := s1.IntraListSimilarity([]Recommendation{{Item: 1}, {Item: 2}})
+	diverse := s1.IntraListSimilarity([]Recommendation{{Item: 1}, {Item: 3}})
+	if !near(homogeneous, 0.75) || diverse >= homogeneous {
+		t.Errorf("got %v for a diverse list and %v for a homogeneous one", diverse, homogeneous)
+	}
+	if got := s1.IntraListSimilarity([]Recommendation{{Item: 1}, {Item: 1}}); got != 0 {
+		t.Errorf("one distinct item: got %v, want 0", got)
+	}
+}
+
+func TestSerendipitousTopN(t *testing.T) {
+	// Item 2 is always rated with the user's favourite, item 1, so it is
+	// the obvious recommendation; item 3 was rated with it only once.
+	var data []UserRatings
+	for k := 0; k < 10; k++ {
+		data = append(data, UserRatings{1: 5, 2: 5})
+	}
+	data = append(data, UserRatings{1: 4, 3: 3.5})
+	s1 := NewS1()
+	s1.AddRatings(data)
+
+	ur := UserRatings{1: 5}
+	if got := s1.SerendipitousTopN(ur, 1, 0); len(got) != 1 || got[0].Item != 2 {
+		t.Errorf("novelty 0: got %v, want item 2", got)
+	}
+	if got := s1.SerendipitousTopN(ur, 1, 2); len(got) != 1 || got[0].Item != 3 {
+		t.Errorf("novelty 2: got %v, want item 3", got)
+	}
+
+	want := s1.TopN(ur, 2)
+	got := s1.SerendipitousTopN(ur, 2, 0)
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for k := range want {
+		if got[k] != want[k] {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestGiniOfRecommendations(t *testing.T) {
+	recs := func(items ...int) []Recommendation {
+		out := make([]Recommendation, len(items))
+		for k, i := range items {
+			out[k].Item = i
+		}
+		return out
+	}
+
+	even := map[int][]Recommendation{1: recs(1, 2), 2: recs(3, 4), 3: recs(5, 6)}
+	if got := GiniOfRecommendations(even); !near(got, 0) {
+		t.Errorf("even: got %v, want 0", got)
+	}
+
+	// Items 1 and 2 are recommended 20 times each, and items 3 to 6
+	// once, so the Gini coefficient of the sorted counts 1, 1, 1, 1, 20
+	// and 20 is 2*(1+2+3+4+5*20+6*20) / (6*44) - 7/6.
+	skewed := map[int][]Recommendation{99: recs(3, 4, 5, 6)}
+	for u := 0; u < 20; u++ {
+		skewed[u] = recs(1, 2)
+	}
+	if got, want := GiniOfRecommendations(skewed), 2.0*230/(6*44)-7.0/6; !near(got, want) {
+		t.Errorf("skewed: got %v, want %v", got, want)
+	}
+
+	if got := GiniOfRecommendations(nil); got != 0 {
+		t.Errorf("no recommendations: got %v, want 0", got)
+	}
+}
@@ -0,0 +1,37 @@
+package slopeone
+
+// SetEMAAlpha makes the deviation of each pair of items an exponential
+// moving average of its rating differences, rather than their plain
+// average, so that the model adapts to drift in how items are rated
+// without windowing. Each difference subsequently observed between a
+// pair of items updates the pair's deviation to
+//
+//	dev = alpha*diff + (1-alpha)*dev
+//
+// so recent differences dominate, the more so the higher alpha is. The
+// first difference observed for a pair is its deviation. Ratings are
+// normalised, as set with SetNormalization, before their differences
+// update the average, so with MeanCentering the average is the same as
+// without normalization, and with ZScore it is of the differences
+// between the users' z-scores. Alphas above 1 are treated as 1, which
+// makes each pair's deviation its latest difference; an alpha of 0 or
+// less, the default, restores plain averages for subsequent
+// differences.
+//
+// A moving average cannot be reversed, so removing ratings from a pair,
+// e.g., with RemoveRatings, reduces its support but leaves its
+// deviation unchanged. Pairs combined by Merge or MergeItems are
+// averaged by their weight.
+//
+// A moving average cannot be combined with trimming or variance
+// weighting; see Config.Validate.
+func (s1 *S1) SetEMAAlpha(alpha float64) {
+	if !(alpha > 0) {
+		alpha = 0
+	}
+	if alpha > 1 {
+		alpha = 1
+	}
+	s1.emaAlpha = alpha
+	s1.gen++
+}
@@ -0,0 +1,37 @@
+package slopeone
+
+import "testing"
+
+func TestSetEMAAlpha(t *testing.T) {
+	old, recent := UserRatings{1: 1, 2: 3}, UserRatings{1: 3, 2: 3}
+	s1 := NewS1()
+	s1.SetEMAAlpha(0.5)
+	plain := NewS1()
+	for _, s := range []*S1{s1, plain} {
+		for k := 0; k < 5; k++ {
+			s.AddRatings([]UserRatings{old})
+		}
+		s.AddRatings([]UserRatings{recent, recent})
+	}
+
+	// Five old differences of 2 and two recent ones of 0. The average
+	// moves from 2 halfway to 0 twice, to 0.5, while the plain average
+	// is 10 / 7.
+	ur := UserRatings{1: 1}
+	if got := s1.Predict(ur)[2]; !near(got, 1.5) {
+		t.Errorf("EMA: got %v, want 1.5", got)
+	}
+	if got := plain.Predict(ur)[2]; !near(got, 1+10.0/7) {
+		t.Errorf("plain: got %v, want %v", got, 1+10.0/7)
+	}
+
+	// The average of a pair cannot be unwound, so removing ratings only
+	// changes its support.
+	s1.RemoveRatings([]UserRatings{old})
+	if got := s1.Predict(ur)[2]; !near(got, 1.5) {
+		t.Errorf("after removal: got %v, want 1.5", got)
+	}
+	if got := s1.Predict(UserRatings{2: 3})[1]; !near(got, 2.5) {
+		t.Errorf("reverse: got %v, want 2.5", got)
+	}
+}
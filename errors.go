@@ -0,0 +1,63 @@
+package slopeone
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnsupportedVersion is returned, wrapped in a *DecodeError,
+	// when a model was encoded in a format version this package does
+	// not support. The model should be rebuilt.
+	ErrUnsupportedVersion = errors.New("slopeone: unsupported model version")
+
+	// ErrCorruptModel is returned, wrapped in a *DecodeError, when
+	// encoded model data is malformed, truncated or not a model.
+	ErrCorruptModel = errors.New("slopeone: corrupt model")
+
+	// ErrIncompatibleConfig is returned, wrapped, when a Config
+	// combines settings that cannot be used together.
+	ErrIncompatibleConfig = errors.New("slopeone: incompatible settings")
+)
+
+// DecodeError describes a failure to decode a model.
+//
+// errors.Is reports whether a DecodeError matches its Kind, and
+// errors.As and errors.Is may also be used to inspect the underlying
+// Err.
+type DecodeError struct {
+	// Kind is ErrUnsupportedVersion or ErrCorruptModel.
+	Kind error
+
+	// Detail describes the failure.
+	Detail string
+
+	// Err is the underlying error, if any.
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	msg := e.Kind.Error()
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Is reports whether target is the DecodeError's Kind.
+func (e *DecodeError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// Unwrap returns the underlying error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// corrupt returns a *DecodeError for corrupt model data.
+func corrupt(format string, args ...any) error {
+	return &DecodeError{Kind: ErrCorruptModel, Detail: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,58 @@
+package slopeone
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDecodeErrors(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newFixture().WriteMapped(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	modify := func(f func(b []byte) []byte) []byte {
+		return f(append([]byte(nil), data...))
+	}
+	tests := []struct {
+		name string
+		data []byte
+		kind error
+	}{
+		{"version", modify(func(b []byte) []byte { b[7] = 9; return b }), ErrUnsupportedVersion},
+		{"magic", modify(func(b []byte) []byte { b[0] = 'X'; return b }), ErrCorruptModel},
+		{"short", data[:mappedHeaderSize-1], ErrCorruptModel},
+		{"truncated", data[:len(data)-3], ErrCorruptModel},
+		{"offset", modify(func(b []byte) []byte {
+			binary.LittleEndian.PutUint64(b[mappedHeaderSize+8:], 1<<40)
+			return b
+		}), ErrCorruptModel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "model")
+			if err := os.WriteFile(path, tt.data, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := OpenMapped(path)
+			if !errors.Is(err, tt.kind) {
+				t.Fatalf("got %v, want %v", err, tt.kind)
+			}
+			for _, other := range []error{ErrUnsupportedVersion, ErrCorruptModel} {
+				if other != tt.kind && errors.Is(err, other) {
+					t.Errorf("%v also matches %v", err, other)
+				}
+			}
+			var de *DecodeError
+			if !errors.As(err, &de) {
+				t.Errorf("got %T, want *DecodeError", err)
+			}
+		})
+	}
+}
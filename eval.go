@@ -0,0 +1,289 @@
+package slopeone
+
+import (
+	"math"
+	"runtime"
+	"sync"
+)
+
+// PrequentialEval evaluates the S1 on a stream of rating events in the
+// order they occurred, and returns the root mean squared error of its
+// predictions.
+//
+// Each event is first predicted from the ratings its user made earlier
+// in the stream, then added to the S1, so every prediction is made by a
+// model that has not seen the rating being predicted. The S1 is trained
+// on the whole stream as a result. Events that cannot be predicted do
+// not count towards the error, which is NaN if no event could be
+// predicted.
+func (s1 *S1) PrequentialEval(stream []RatingEvent) float64 {
+	return s1.PrequentialEvalWithCallback(stream, 0, nil)
+}
+
+// PrequentialEvalWithCallback is like PrequentialEval, but also calls
+// report after every "every" events, and after the final event, with the
+// number of events processed so far and the error up to that point,
+// e.g., to plot a learning curve. If every is 0 or less report is only
+// called after the final event. report may be nil.
+func (s1 *S1) PrequentialEvalWithCallback(stream []RatingEvent, every int, report func(processed int, runningRMSE float64)) float64 {
+	profiles := make(map[int]UserRatings)
+	var sq float64
+	var n int
+	rmse := func() float64 {
+		if n == 0 {
+			return math.NaN()
+		}
+		return math.Sqrt(sq / float64(n))
+	}
+
+	for k, ev := range stream {
+		profile, ok := profiles[ev.User]
+		if !ok {
+			profile = make(UserRatings)
+			profiles[ev.User] = profile
+		}
+
+		if p, ok := s1.PredictItem(profile, ev.Item); ok {
+			sq += (p - ev.Rating) * (p - ev.Rating)
+			n++
+		}
+		s1.extend(profile, ev.Item, ev.Rating)
+
+		if report != nil && ((every > 0 && (k+1)%every == 0) || k == len(stream)-1) {
+			report(k+1, rmse())
+		}
+	}
+	s1.evict()
+	return rmse()
+}
+
+// PrecisionRecallAtK evaluates the S1's top-k recommendations, as made
+// by TopN, for each of the users in test, which maps user ids to the
+// ratings their recommendations are made from, against relevant, which
+// maps user ids to the held-out items relevant to them.
+//
+// For each user, precision is the fraction of the k recommendations
+// that are relevant, counting missing recommendations as irrelevant,
+// and recall is the fraction of the relevant items that are
+// recommended. Both are averaged over the users in test with at least
+// one relevant item, and are NaN if there are none.
+func (s1 *S1) PrecisionRecallAtK(test map[int]UserRatings, relevant map[int][]int, k int) (precision, recall float64) {
+	if k <= 0 {
+		return math.NaN(), math.NaN()
+	}
+
+	var users int
+	for user, ur := range test {
+		rel := make(map[int]bool, len(relevant[user]))
+		for _, i := range relevant[user] {
+			rel[i] = true
+		}
+		if len(rel) == 0 {
+			continue
+		}
+
+		var hits int
+		for _, rec := range s1.TopN(ur, k) {
+			if rel[rec.Item] {
+				hits++
+			}
+		}
+		precision += float64(hits) / float64(k)
+		recall += float64(hits) / float64(len(rel))
+		users++
+	}
+
+	if users == 0 {
+		return math.NaN(), math.NaN()
+	}
+	return precision / float64(users), recall / float64(users)
+}
+
+// ItemPredictability returns, for each item rated in test, the mean
+// absolute error of the S1's predictions of its ratings, e.g., to find
+// the items that are predicted poorly and would benefit from more data.
+//
+// Each rating in test is predicted, leave-one-out, from the same user's
+// other ratings, as by PredictAll. The users in test should not have
+// been added to the S1, or its predictions will be based on the very
+// ratings being predicted. Items none of whose ratings can be predicted
+// are omitted.
+func (s1 *S1) ItemPredictability(test []UserRatings) map[int]float64 {
+	errs := make(map[int]float64)
+	n := make(map[int]int)
+	for _, ur := range test {
+		for i, p := range s1.PredictAll(ur) {
+			if r, ok := ur[i]; ok {
+				errs[i] += math.Abs(p - r)
+				n[i]++
+			}
+		}
+	}
+
+	for i := range errs {
+		errs[i] /= float64(n[i])
+	}
+	return errs
+}
+
+// LOUOEval evaluates the S1 by leave-one-user-out, and returns the root
+// mean squared error and mean absolute error of its predictions. data
+// must be ratings the S1 was trained on, exactly as they were added,
+// e.g., every user it was trained on.
+//
+// Each user in data is held out in turn: their ratings are removed from
+// the model, as by RemoveRatings, each of their ratings is predicted
+// from their other ratings, as by PredictAll, and their ratings are
+// then added back. Rather than the model being retrained for every
+// user, the users are split into contiguous chunks, each evaluated in
+// parallel on its own copy of the S1, using up to workers goroutines,
+// or as many as can run simultaneously if workers is less than 1. The
+// S1 itself is not modified.
+//
+// The errors are aggregated in the order of data, so they are
+// deterministic for a given number of workers, and equal to those of
+// retraining without each user up to floating-point rounding. Ratings
+// that cannot be predicted do not count towards the errors, which are
+// NaN if no rating could be predicted. As a moving average cannot be
+// reversed, the results for an S1 with SetEMAAlpha are approximate.
+func (s1 *S1) LOUOEval(data []UserRatings, workers int) (rmse, mae float64) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(data) {
+		workers = len(data)
+	}
+
+	type result struct {
+		sq, abs float64
+		n       int
+	}
+	results := make([]result, len(data))
+	if workers > 0 {
+		size := (len(data) + workers - 1) / workers
+		var wg sync.WaitGroup
+		for lo := 0; lo < len(data); lo += size {
+			hi := lo + size
+			if hi > len(data) {
+				hi = len(data)
+			}
+
+			cp := s1.withOptions()
+			cp.observer, cp.autoCompact = nil, 0
+			cp.Merge(s1)
+			wg.Add(1)
+			go func(lo, hi int) {
+				defer wg.Done()
+				for u := lo; u < hi; u++ {
+					ur := data[u : u+1]
+					cp.RemoveRatings(ur)
+					res := &results[u]
+					for i, p := range cp.PredictAll(ur[0]) {
+						r, ok := ur[0][i]
+						if !ok {
+							continue
+						}
+						d := p - r
+						res.sq += d * d
+						res.abs += math.Abs(d)
+						res.n++
+					}
+					cp.AddRatings(ur)
+				}
+			}(lo, hi)
+		}
+		wg.Wait()
+	}
+
+	var sq, abs float64
+	var n int
+	for _, res := range results {
+		sq, abs, n = sq+res.sq, abs+res.abs, n+res.n
+	}
+	if n == 0 {
+		return math.NaN(), math.NaN()
+	}
+	return math.Sqrt(sq / float64(n)), abs / float64(n)
+}
+
+// ItemImportance returns, for each of items, how much the S1's
+// accuracy on test depends on it: the increase in the root mean
+// squared error of predicting the ratings in test when the item is
+// removed from the model, as by RemoveItem. Items of near-zero, or
+// negative, importance are candidates for pruning.
+//
+// Each rating in test is predicted, leave-one-out, from the same user's
+// other ratings, as by PredictAll; predictions of items the user did
+// not rate have nothing to be compared with, and are ignored. For each
+// item, the error is compared over the ratings that can be predicted
+// both with and without it, excluding the ratings of the item itself,
+// so items that are the only evidence for some predictions are judged
+// only by the predictions they improve. An item for which no such
+// ratings exist has an importance of 0. The users in test should not
+// have been added to the S1.
+//
+// The items are removed from a copy of the S1, one at a time, and
+// restored before the next is removed, so the S1 itself is not
+// modified.
+func (s1 *S1) ItemImportance(test []UserRatings, items []int) map[int]float64 {
+	base := make([]map[int]float64, len(test))
+	for u, ur := range test {
+		base[u] = s1.PredictAll(ur)
+	}
+
+	cp := s1.withOptions()
+	cp.observer, cp.autoCompact = nil, 0
+	cp.Merge(s1)
+
+	out := make(map[int]float64, len(items))
+	for _, item := range items {
+		if _, ok := out[item]; ok {
+			continue
+		}
+
+		// The item's pairs and ratings are restored exactly, as each is
+		// added back to an empty entry.
+		type pair struct {
+			i1, i2 int
+			p      pairStats
+		}
+		var pairs []pair
+		for j := range cp.f[item] {
+			pairs = append(pairs, pair{item, j, cp.stats(item, j)})
+			if j != item {
+				pairs = append(pairs, pair{j, item, cp.stats(j, item)})
+			}
+		}
+		n, sum := cp.counts[item], cp.sums[item]
+		cp.RemoveItem(item)
+
+		var before, after float64
+		var k int
+		for u, ur := range test {
+			preds := cp.PredictAll(ur)
+			for i, p := range base[u] {
+				r, rated := ur[i]
+				q, ok := preds[i]
+				if i == item || !rated || !ok {
+					continue
+				}
+				before += (p - r) * (p - r)
+				after += (q - r) * (q - r)
+				k++
+			}
+		}
+		out[item] = 0
+		if k > 0 {
+			out[item] = math.Sqrt(after/float64(k)) - math.Sqrt(before/float64(k))
+		}
+
+		for _, p := range pairs {
+			cp.addRaw(p.i1, p.i2, p.p)
+		}
+		if n > 0 {
+			cp.counts[item], cp.sums[item] = n, sum
+			cp.ratings += int64(n)
+		}
+	}
+	return out
+}
@@ -0,0 +1,174 @@
+package slopeone
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPrequentialEval(t *testing.T) {
+	data := GenerateRatings(300, 30, 0.3, 4)
+	var stream []RatingEvent
+	for u, ur := range data {
+		for i, r := range ur {
+			stream = append(stream, RatingEvent{User: u, Item: i, Rating: r})
+		}
+	}
+
+	var running []float64
+	s1 := NewS1()
+	final := s1.PrequentialEvalWithCallback(stream, 100, func(processed int, rmse float64) {
+		running = append(running, rmse)
+	})
+	if math.IsNaN(final) || final > 1.5 {
+		t.Fatalf("got final RMSE %v", final)
+	}
+	// There is a report every 100 events, and after the final event.
+	if n := (len(stream) + 99) / 100; len(running) != n || running[n-1] != final {
+		t.Fatalf("got %d reports ending %v, want %d ending %v", len(running), running[len(running)-1], n, final)
+	}
+
+	// The running RMSE settles down as the model learns, so the later
+	// reports are closer to the final value than the earliest.
+	dist := func(r []float64) float64 {
+		var d float64
+		for _, v := range r {
+			d = math.Max(d, math.Abs(v-final))
+		}
+		return d
+	}
+	q := len(running) / 4
+	if early, late := dist(running[1:q]), dist(running[3*q:]); late >= early || late > 0.05 {
+		t.Errorf("got distance %v from final RMSE early, %v late", early, late)
+	}
+
+	want := NewS1()
+	want.AddRatings(data)
+	if got, w := s1.Predict(data[0]), want.Predict(data[0]); !samePredictions(got, w) {
+		t.Errorf("trained model: got %v, want %v", got, w)
+	}
+}
+
+func TestPrecisionRecallAtK(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{{1: 3, 2: 5, 3: 4, 4: 1}})
+
+	// From a rating of item 1, the top 2 are items 2 and 3. User 1 has
+	// one hit of two relevant items, and user 2 one hit of four. User
+	// 3 has no relevant items, so is not counted.
+	test := map[int]UserRatings{1: {1: 3}, 2: {1: 3}, 3: {1: 3}}
+	relevant := map[int][]int{1: {2, 4}, 2: {3, 9, 8, 7}}
+	precision, recall := s1.PrecisionRecallAtK(test, relevant, 2)
+	if !near(precision, 0.5) || !near(recall, (0.5+0.25)/2) {
+		t.Errorf("got precision %v and recall %v, want 0.5 and 0.375", precision, recall)
+	}
+}
+
+func TestItemPredictability(t *testing.T) {
+	// Item 3 alternates between ratings of 1 and 5 for users who agree
+	// about items 1 and 2.
+	var train []UserRatings
+	for k := 0; k < 6; k++ {
+		hard := 1.0
+		if k%2 == 0 {
+			hard = 5
+		}
+		train = append(train, UserRatings{1: 3, 2: 4, 3: hard})
+	}
+	s1 := NewS1()
+	s1.AddRatings(train)
+
+	got := s1.ItemPredictability([]UserRatings{{1: 3, 2: 4, 3: 5}, {1: 2, 2: 3, 3: 1}})
+	if !(got[3] > got[1]) || !(got[3] > got[2]) {
+		t.Errorf("got %v, want item 3 to have the highest error", got)
+	}
+	if _, ok := got[9]; ok {
+		t.Errorf("got an error for unknown item 9: %v", got)
+	}
+}
+
+func TestLOUOEval(t *testing.T) {
+	data := GenerateRatings(40, 15, 0.4, 7)
+	s1 := NewS1()
+	s1.AddRatings(data)
+	fp := s1.Fingerprint()
+
+	// Train a model without each user in turn, and predict each of the
+	// user's ratings from their others.
+	var sq, abs float64
+	var n int
+	for u := range data {
+		m := NewS1()
+		for v := range data {
+			if v != u {
+				m.AddRatings(data[v : v+1])
+			}
+		}
+		for i, p := range m.PredictAll(data[u]) {
+			r, ok := data[u][i]
+			if !ok {
+				continue
+			}
+			d := p - r
+			sq += d * d
+			abs += math.Abs(d)
+			n++
+		}
+	}
+	wantRMSE, wantMAE := math.Sqrt(sq/float64(n)), abs/float64(n)
+
+	for _, workers := range []int{0, 1, 3, 100} {
+		rmse, mae := s1.LOUOEval(data, workers)
+		if !near(rmse, wantRMSE) || !near(mae, wantMAE) {
+			t.Errorf("%d workers: got %v, %v, want %v, %v", workers, rmse, mae, wantRMSE, wantMAE)
+		}
+	}
+	if s1.Fingerprint() != fp {
+		t.Error("the S1 was modified")
+	}
+	// Every rating is 5, so every held-out rating is predicted exactly,
+	// although the second user's item 3 is predicted for the others too.
+	uniform := []UserRatings{{1: 5, 2: 5}, {1: 5, 2: 5, 3: 5}, {1: 5, 2: 5}}
+	s1 = NewS1()
+	s1.AddRatings(uniform)
+	if rmse, mae := s1.LOUOEval(uniform, 2); rmse != 0 || mae != 0 {
+		t.Errorf("uniform: got %v, %v, want 0, 0", rmse, mae)
+	}
+
+	if rmse, _ := NewS1().LOUOEval(nil, 2); !math.IsNaN(rmse) {
+		t.Errorf("no users: got %v, want NaN", rmse)
+	}
+}
+
+func TestItemImportance(t *testing.T) {
+	// Item 2 is always rated 1 above item 1, while item 3's ratings are
+	// unrelated to either.
+	var data []UserRatings
+	for k := 0; k < 20; k++ {
+		data = append(data, UserRatings{1: float64(k % 4), 2: float64(k%4) + 1, 3: float64(k * 7 % 5)})
+	}
+	s1 := NewS1()
+	s1.AddRatings(data)
+	fp := s1.Fingerprint()
+
+	test := []UserRatings{{1: 2, 2: 3, 3: 0}, {1: 0, 2: 1, 3: 4}}
+	got := s1.ItemImportance(test, []int{1, 3, 1, 99})
+	if len(got) != 3 || !(got[1] > got[3]) || got[99] != 0 {
+		t.Errorf("got %v, want item 1 more important than item 3, and item 99 unimportant", got)
+	}
+	if s1.Fingerprint() != fp {
+		t.Error("the S1 was modified")
+	}
+}
+
+func TestItemImportanceUnrated(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{{1: 3, 2: 4}, {1: 3, 2: 4}, {5: 2, 9: 4}, {1: 3, 9: 1}})
+
+	// Item 5 is rated by the test user, but is co-rated only with item
+	// 9, which they did not rate. Removing it changes the prediction of
+	// item 9 alone, so it does not matter to the test's ratings.
+	test := []UserRatings{{1: 3, 2: 4, 5: 2}}
+	if got := s1.ItemImportance(test, []int{5}); got[5] != 0 {
+		t.Errorf("got %v, want an importance of 0", got)
+	}
+}
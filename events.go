@@ -0,0 +1,66 @@
+package slopeone
+
+// RatingEvent is a single observed interaction between a user and an
+// item, such as a click, a skip or a purchase.
+type RatingEvent struct {
+	User int
+	Item int
+
+	// Type describes the kind of interaction, e.g. "skip" or
+	// "purchase". It is not interpreted by the package.
+	Type string
+
+	// Rating is an explicit rating carried by the event, if any.
+	Rating float64
+}
+
+// AddEvents adds a stream of interaction events to the S1.
+//
+// Each event is converted to a rating using mapping. Events for which
+// mapping returns false as its second value are skipped. The remaining
+// ratings are grouped by user and added as if by AddRatings.
+//
+// When a user has several events for the same item, their mapped
+// ratings are summed, so that, for example, a weak negative "skip"
+// followed by a strong positive "purchase" combine into a single rating
+// for that item.
+func (s1 *S1) AddEvents(events []RatingEvent, mapping func(RatingEvent) (float64, bool)) {
+	var users []UserRatings
+	idx := make(map[int]int)
+	for _, ev := range events {
+		r, ok := mapping(ev)
+		if !ok {
+			continue
+		}
+
+		i, ok := idx[ev.User]
+		if !ok {
+			i = len(users)
+			idx[ev.User] = i
+			users = append(users, make(UserRatings))
+		}
+		users[i][ev.Item] += r
+	}
+	s1.AddRatings(users)
+}
+
+// PredictFromItemSet returns predicted ratings for a user known only
+// by a set of items they interacted with, e.g., viewed, rather than by
+// explicit ratings, treating each of items as rated impliedRating. It
+// is equivalent to calling Predict with a UserRatings giving each of
+// items that rating.
+//
+// As every item the user interacted with is given the same rating,
+// each prediction is impliedRating adjusted by the weighted average
+// deviation of the predicted item from the user's items, so the
+// predictions rank items by how much better or worse they are rated
+// than the user's items by the users who rated both. impliedRating
+// should be chosen on the scale the S1 was trained on, e.g., the rating
+// a view typically corresponds to, or a probability in log-odds mode.
+func (s1 *S1) PredictFromItemSet(items []int, impliedRating float64) map[int]float64 {
+	ur := make(UserRatings, len(items))
+	for _, i := range items {
+		ur[i] = impliedRating
+	}
+	return s1.Predict(ur)
+}
@@ -0,0 +1,49 @@
+package slopeone
+
+import "testing"
+
+func TestAddEvents(t *testing.T) {
+	mapping := func(ev RatingEvent) (float64, bool) {
+		switch ev.Type {
+		case "skip":
+			return -1, true
+		case "purchase":
+			return 5, true
+		}
+		return 0, false
+	}
+
+	s1 := NewS1()
+	s1.AddEvents([]RatingEvent{
+		{User: 1, Item: 1, Type: "purchase"},
+		{User: 1, Item: 2, Type: "skip"},
+		{User: 1, Item: 2, Type: "view"},
+		{User: 2, Item: 1, Type: "purchase"},
+		{User: 2, Item: 1, Type: "skip"},
+	}, mapping)
+
+	// User 1 rated item 1 5 and item 2 -1, the view being ignored.
+	// User 2's events for item 1 sum to 4, but they rated nothing else.
+	want := NewS1()
+	want.AddRatings([]UserRatings{{1: 5, 2: -1}, {1: 4}})
+
+	ur := UserRatings{1: 4}
+	if got, w := s1.Predict(ur), want.Predict(ur); !samePredictions(got, w) {
+		t.Errorf("got %v, want %v", got, w)
+	}
+	if got := s1.Predict(ur)[2]; !near(got, -2) {
+		t.Errorf("got %v, want -2", got)
+	}
+	if got := s1.NumRatings(); got != 3 {
+		t.Errorf("got %d ratings, want 3", got)
+	}
+}
+
+func TestPredictFromItemSet(t *testing.T) {
+	s1 := newFixture()
+	got := s1.PredictFromItemSet([]int{2005, 29074, 2005}, 4)
+	want := s1.Predict(UserRatings{2005: 4, 29074: 4})
+	if len(want) == 0 || !samePredictions(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
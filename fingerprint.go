@@ -0,0 +1,64 @@
+package slopeone
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+)
+
+// Fingerprint returns a hash of the S1's average rating differences
+// and frequencies, e.g., to verify that two deployments loaded the same
+// model. Models holding identical pairs of items always have the same
+// fingerprint, regardless of the order their contents are stored in.
+//
+// The fingerprint is maintained as pairs are added and removed, so
+// Fingerprint is cheap enough to call after every update, e.g., to
+// detect whether the model changed between two checkpoints.
+func (s1 *S1) Fingerprint() uint64 {
+	return s1.fp
+}
+
+// fingerprint recomputes the fingerprint of the S1 from all of its
+// pairs.
+func (s1 *S1) fingerprint() uint64 {
+	var sum uint64
+	for i1, freqs := range s1.f {
+		for i2, n := range freqs {
+			dev, _ := s1.deviation(i1, i2)
+			sum += pairHash(i1, i2, dev, n)
+		}
+	}
+	return sum
+}
+
+// unhash removes the hash of the pair i1, i2, if the S1 holds it, from
+// the fingerprint, before the pair is changed.
+func (s1 *S1) unhash(i1, i2 int) {
+	if n, ok := s1.f[i1][i2]; ok {
+		dev, _ := s1.deviation(i1, i2)
+		s1.fp -= pairHash(i1, i2, dev, n)
+	}
+}
+
+// rehash adds the hash of the pair i1, i2, if the S1 holds it, to the
+// fingerprint, after the pair has changed.
+func (s1 *S1) rehash(i1, i2 int) {
+	if n, ok := s1.f[i1][i2]; ok {
+		dev, _ := s1.deviation(i1, i2)
+		s1.fp += pairHash(i1, i2, dev, n)
+	}
+}
+
+// pairHash returns the hash of a single pair of items with the given
+// average rating difference and frequency. Pair hashes are summed, so
+// that the fingerprint does not depend on the order pairs are visited.
+func pairHash(i1, i2 int, dev float64, n int) uint64 {
+	var buf [32]byte
+	binary.LittleEndian.PutUint64(buf[0:], uint64(i1))
+	binary.LittleEndian.PutUint64(buf[8:], uint64(i2))
+	binary.LittleEndian.PutUint64(buf[16:], math.Float64bits(dev))
+	binary.LittleEndian.PutUint64(buf[24:], uint64(n))
+	h := fnv.New64a()
+	h.Write(buf[:])
+	return h.Sum64()
+}
@@ -0,0 +1,57 @@
+package slopeone
+
+import "testing"
+
+func TestFingerprint(t *testing.T) {
+	a := newFixture()
+	b := NewS1()
+	for k := len(fixture) - 1; k >= 0; k-- {
+		b.AddRatings([]UserRatings{fixture[k]})
+	}
+	if a.Fingerprint() != b.Fingerprint() {
+		t.Errorf("equivalent models: got %x and %x", a.Fingerprint(), b.Fingerprint())
+	}
+
+	b.AddRatings([]UserRatings{{1: 3, 2: 4.5}})
+	if a.Fingerprint() == b.Fingerprint() {
+		t.Error("changed model has the same fingerprint")
+	}
+}
+
+func TestFingerprintIncremental(t *testing.T) {
+	data := GenerateRatings(40, 20, 0.3, 4)
+	s1 := NewS1()
+	check := func(step string) {
+		t.Helper()
+		if got, want := s1.Fingerprint(), s1.fingerprint(); got != want {
+			t.Errorf("after %s: got %x, want %x", step, got, want)
+		}
+	}
+
+	s1.AddRatings(data)
+	check("AddRatings")
+	s1.RemoveRatings(data[:10])
+	check("RemoveRatings")
+	s1.UpsertUser(1, data[3])
+	s1.UpsertUser(1, data[4])
+	check("UpsertUser")
+	s1.MergeItems(1, 2)
+	check("MergeItems")
+	s1.PruneBelowSupport(2)
+	check("PruneBelowSupport")
+	s1.Compact()
+	check("Compact")
+
+	if s1.Fingerprint() == 0 {
+		t.Error("got a zero fingerprint for a non-empty model")
+	}
+
+	// Removing everything that was added leaves the fingerprint of an
+	// empty model.
+	s1 = NewS1()
+	s1.AddRatings(data[:5])
+	s1.RemoveRatings(data[:5])
+	if got := s1.Fingerprint(); got != 0 {
+		t.Errorf("emptied model: got %x, want 0", got)
+	}
+}
@@ -0,0 +1,57 @@
+package slopeone
+
+// FreezeItems locks the S1's item vocabulary, e.g., once a known
+// catalog has been loaded, so that training no longer adds new items.
+// While the items are frozen, ratings of items the S1 does not already
+// hold are skipped by AddRatings, which reports how many it skipped,
+// and by every other method that adds ratings. Ratings of known items
+// are added as normal.
+func (s1 *S1) FreezeItems() {
+	s1.frozenItems = true
+}
+
+// UnfreezeItems reverses FreezeItems, so that training adds new items
+// again.
+func (s1 *S1) UnfreezeItems() {
+	s1.frozenItems = false
+}
+
+// isKnown reports whether ratings of item i may be added to the S1: its
+// items are not frozen, or it already holds i.
+func (s1 *S1) isKnown(i int) bool {
+	if !s1.frozenItems {
+		return true
+	}
+	if _, ok := s1.f[i]; ok {
+		return true
+	}
+	_, ok := s1.counts[i]
+	return ok
+}
+
+// known returns ur without the ratings of items that may not be added
+// to the S1, and the number of ratings removed. If there are none, ur
+// itself is returned.
+func (s1 *S1) known(ur UserRatings) (UserRatings, int) {
+	if !s1.frozenItems {
+		return ur, 0
+	}
+
+	var unknown int
+	for i := range ur {
+		if !s1.isKnown(i) {
+			unknown++
+		}
+	}
+	if unknown == 0 {
+		return ur, 0
+	}
+
+	cp := make(UserRatings, len(ur)-unknown)
+	for i, r := range ur {
+		if s1.isKnown(i) {
+			cp[i] = r
+		}
+	}
+	return cp, unknown
+}
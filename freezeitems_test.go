@@ -0,0 +1,45 @@
+package slopeone
+
+import "testing"
+
+func TestFreezeItems(t *testing.T) {
+	s1 := NewS1()
+	if n := s1.AddRatings([]UserRatings{{1: 3, 2: 4}}); n != 0 {
+		t.Fatalf("got %d ratings skipped, want 0", n)
+	}
+
+	s1.FreezeItems()
+	if n := s1.AddRatings([]UserRatings{{1: 5, 2: 4, 3: 1}, {9: 1}}); n != 2 {
+		t.Errorf("got %d ratings skipped, want 2", n)
+	}
+	if s1.NumRatings() != 4 || s1.NumPairs() != 1 || s1.ItemRatingCount(1) != 2 {
+		t.Errorf("got %d ratings and %d pairs, want known items updated only", s1.NumRatings(), s1.NumPairs())
+	}
+	if _, ok := s1.Predict(UserRatings{1: 1})[3]; ok {
+		t.Error("rejected item 3 was predicted")
+	}
+	s1.RemoveRatings([]UserRatings{{1: 5, 2: 4, 3: 1}})
+	if got := s1.NumRatings(); got != 2 {
+		t.Errorf("after removal: got %d ratings, want 2", got)
+	}
+
+	s1.UnfreezeItems()
+	s1.AddRatings([]UserRatings{{3: 1, 1: 1}})
+	if got := s1.NumPairs(); got != 2 {
+		t.Errorf("unfrozen: got %d pairs, want 2", got)
+	}
+}
+
+func TestFreezeItemsWindowed(t *testing.T) {
+	ws := NewWindowedS1(1)
+	ws.AddRatings([]UserRatings{{1: 1}})
+	ws.FreezeItems()
+	if n := ws.AddRatings([]UserRatings{{1: 1, 2: 2}}); n != 1 {
+		t.Errorf("got %d ratings skipped, want 1", n)
+	}
+	// Only the known rating was added, so it is all that is removed.
+	ws.AddRatings(nil)
+	if got := ws.NumRatings(); got != 0 {
+		t.Errorf("got %d ratings, want 0", got)
+	}
+}
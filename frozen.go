@@ -0,0 +1,145 @@
+package slopeone
+
+import "sort"
+
+// FrozenS1 is a read-only snapshot of an S1, built by Freeze, for
+// serving predictions.
+//
+// Rather than maps, a FrozenS1 holds the model in flat, compressed
+// sparse row arrays: the pairs of each item are stored contiguously,
+// sorted by the other item, so predictions read memory sequentially
+// and visit pairs in a deterministic order.
+type FrozenS1 struct {
+	// options holds the settings of the S1 when it was frozen.
+	options
+
+	// items holds the items with pairs, sorted. The pairs of items[k]
+	// are at indices offsets[k] to offsets[k+1] of the pair arrays.
+	items   []int
+	offsets []int
+
+	// The pair arrays hold, for the pairs of item i, the other item gi;
+	// the sum, total weight and number of the rating differences
+	// between gi and i; and, if variance weighting was enabled, their
+	// variance.
+	others    []int
+	sums      []float64
+	weights   []float64
+	freqs     []int
+	variances []float64
+
+	// priors holds the mean rating of each item, as used in the
+	// model's calculations, if predictions are shrunk.
+	priors map[int]float64
+}
+
+// Freeze returns a snapshot of the S1 for serving predictions, which
+// are the same as those made by the S1 at the time of the call. The S1
+// can continue to be trained without affecting the snapshot.
+func (s1 *S1) Freeze() *FrozenS1 {
+	fz := &FrozenS1{
+		options: s1.options.clone(),
+		items:   make([]int, 0, len(s1.f)),
+		offsets: make([]int, 1, len(s1.f)+1),
+	}
+	var pairs int
+	for i, freqs := range s1.f {
+		fz.items = append(fz.items, i)
+		pairs += len(freqs)
+	}
+	sort.Ints(fz.items)
+
+	fz.others = make([]int, 0, pairs)
+	fz.sums = make([]float64, 0, pairs)
+	fz.weights = make([]float64, 0, pairs)
+	fz.freqs = make([]int, 0, pairs)
+	if s1.sq != nil {
+		fz.variances = make([]float64, 0, pairs)
+	}
+
+	for _, i := range fz.items {
+		start := len(fz.others)
+		for gi := range s1.f[i] {
+			fz.others = append(fz.others, gi)
+		}
+		row := fz.others[start:]
+		sort.Ints(row)
+
+		for _, gi := range row {
+			fz.sums = append(fz.sums, s1.pairSum(gi, i, s1.weight(gi, i)))
+			fz.weights = append(fz.weights, s1.weight(gi, i))
+			fz.freqs = append(fz.freqs, s1.f[gi][i])
+			if fz.variances != nil {
+				v, _ := s1.PairVariance(gi, i)
+				fz.variances = append(fz.variances, v)
+			}
+		}
+		fz.offsets = append(fz.offsets, len(fz.others))
+	}
+
+	if s1.shrinkage > 0 {
+		fz.priors = make(map[int]float64, len(s1.counts))
+		for i := range s1.counts {
+			var e estimate
+			if s1.setPrior(&e, i); e.hasPrior {
+				fz.priors[i] = e.prior
+			}
+		}
+	}
+	return fz
+}
+
+// row returns the range of pair indices for item i, if i has pairs.
+func (fz *FrozenS1) row(i int) (start, end int, ok bool) {
+	k := sort.SearchInts(fz.items, i)
+	if k == len(fz.items) || fz.items[k] != i {
+		return 0, 0, false
+	}
+	return fz.offsets[k], fz.offsets[k+1], true
+}
+
+// Predict returns predicted ratings for items the provided user has not
+// yet rated, as S1.Predict does for the model that was frozen.
+func (fz *FrozenS1) Predict(ur UserRatings) map[int]float64 {
+	rated := make([]int, 0, len(ur))
+	for i := range ur {
+		rated = append(rated, i)
+	}
+	sort.Ints(rated)
+
+	est := make(map[int]estimate)
+	sd := fz.spread(ur)
+	for _, i := range rated {
+		r := ur[i]
+		if r < fz.floor {
+			continue
+		}
+		r = fz.in(r)
+
+		start, end, ok := fz.row(i)
+		if !ok {
+			continue
+		}
+		for k := start; k < end; k++ {
+			gi := fz.others[k]
+			if gi == i || fz.weights[k] == 0 || fz.freqs[k] < fz.minSupport {
+				continue
+			}
+			if _, ok := ur[gi]; ok {
+				continue
+			}
+
+			var v float64
+			if fz.variances != nil {
+				v = fz.variances[k]
+			}
+			e := est[gi]
+			e.add(&fz.options, fz.sums[k]*sd, fz.weights[k], fz.freqs[k], v, r)
+			if p, ok := fz.priors[gi]; ok {
+				e.prior, e.hasPrior = p, true
+			}
+			est[gi] = e
+		}
+	}
+	return fz.finishAll(est)
+}
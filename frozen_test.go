@@ -0,0 +1,63 @@
+package slopeone
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	data := GenerateRatings(80, 30, 0.3, 1)
+	settings := map[string]func(s1 *S1){
+		"default": func(s1 *S1) {},
+		"variance": func(s1 *S1) {
+			s1.SetVarianceWeighting(true)
+			s1.SetMinSupport(2)
+		},
+		"scaled": func(s1 *S1) {
+			s1.SetRatingScale(1, 5)
+			s1.SetSourceRatingFloor(2)
+		},
+	}
+	for name, set := range settings {
+		s1 := NewS1()
+		set(s1)
+		s1.AddRatings(data)
+		fz := s1.Freeze()
+
+		rnd := rand.New(rand.NewSource(2))
+		profiles := make([]UserRatings, 20)
+		for k := range profiles {
+			profiles[k] = make(UserRatings)
+			for i, r := range data[k] {
+				if rnd.Intn(2) == 0 {
+					profiles[k][i] = r
+				}
+			}
+		}
+
+		want := make([]map[int]float64, len(profiles))
+		for k, ur := range profiles {
+			want[k] = s1.Predict(ur)
+		}
+		// Training the S1 further does not affect the snapshot.
+		s1.AddRatings(data[:3])
+		for k, ur := range profiles {
+			if got := fz.Predict(ur); !samePredictions(got, want[k]) {
+				t.Errorf("%s, user %d: got %v, want %v", name, k, got, want[k])
+			}
+		}
+	}
+}
+
+// BenchmarkFrozenPredict predicts from the same model and user as
+// BenchmarkPredict, for comparison.
+func BenchmarkFrozenPredict(b *testing.B) {
+	s1 := NewS1()
+	s1.AddRatings(GenerateRatings(1000, 1000, 0.02, 1))
+	fz := s1.Freeze()
+	ur := GenerateRatings(1, 1000, 0.02, 2)[0]
+	b.ResetTimer()
+	for k := 0; k < b.N; k++ {
+		fz.Predict(ur)
+	}
+}
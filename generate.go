@@ -0,0 +1,45 @@
+package slopeone
+
+import (
+	"math"
+	"math/rand"
+)
+
+// GenerateRatings returns synthetic, sparse rating data for use in
+// benchmarks and examples.
+//
+// Each of the users rates each of the items, numbered from 0, with
+// probability density, so users rate roughly density*items items. So
+// that the data has some structure, every item has an underlying
+// quality and every user a bias, and ratings are those plus some
+// noise, rounded to the nearest half star on a scale of 1 to 5.
+//
+// The same arguments always produce the same data.
+func GenerateRatings(users, items int, density float64, seed int64) []UserRatings {
+	return GenerateRatingsWithRand(users, items, density, rand.New(rand.NewSource(seed)))
+}
+
+// GenerateRatingsWithRand is like GenerateRatings, but draws the data
+// from rnd, e.g., one shared by a series of generated data sets, or
+// backed by a source of the caller's choosing.
+func GenerateRatingsWithRand(users, items int, density float64, rnd *rand.Rand) []UserRatings {
+	quality := make([]float64, items)
+	for i := range quality {
+		quality[i] = 1 + 4*rnd.Float64()
+	}
+
+	data := make([]UserRatings, users)
+	for u := range data {
+		bias := rnd.NormFloat64() * 0.5
+		ur := make(UserRatings, int(density*float64(items))+1)
+		for i := 0; i < items; i++ {
+			if rnd.Float64() >= density {
+				continue
+			}
+			r := quality[i] + bias + rnd.NormFloat64()*0.5
+			ur[i] = math.Max(1, math.Min(5, math.Round(r*2)/2))
+		}
+		data[u] = ur
+	}
+	return data
+}
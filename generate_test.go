@@ -0,0 +1,54 @@
+package slopeone
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateRatings(t *testing.T) {
+	const users, items, density = 200, 100, 0.1
+	data := GenerateRatings(users, items, density, 1)
+	if len(data) != users {
+		t.Fatalf("got %d users, want %d", len(data), users)
+	}
+
+	var n int
+	for _, ur := range data {
+		n += len(ur)
+		for i, r := range ur {
+			if i < 0 || i >= items || r < 1 || r > 5 {
+				t.Fatalf("got rating %v of item %d", r, i)
+			}
+		}
+	}
+	// The number of ratings is binomial, with a standard deviation of
+	// about 42.
+	if want := density * users * items; float64(n) < 0.9*want || float64(n) > 1.1*want {
+		t.Errorf("got %d ratings, want about %v", n, want)
+	}
+
+	if again := GenerateRatings(users, items, density, 1); !reflect.DeepEqual(data, again) {
+		t.Error("same seed generated different data")
+	}
+	if other := GenerateRatings(users, items, density, 2); reflect.DeepEqual(data, other) {
+		t.Error("different seeds generated the same data")
+	}
+}
+
+func TestGenerateRatingsWithRand(t *testing.T) {
+	got := GenerateRatingsWithRand(20, 10, 0.5, rand.New(rand.NewSource(3)))
+	if want := GenerateRatings(20, 10, 0.5, 3); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func BenchmarkPredict(b *testing.B) {
+	s1 := NewS1()
+	s1.AddRatings(GenerateRatings(1000, 1000, 0.02, 1))
+	ur := GenerateRatings(1, 1000, 0.02, 2)[0]
+	b.ResetTimer()
+	for k := 0; k < b.N; k++ {
+		s1.Predict(ur)
+	}
+}
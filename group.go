@@ -0,0 +1,62 @@
+package slopeone
+
+import "math"
+
+// GroupStrategy determines how the predicted ratings of a group's
+// members are combined into a prediction for the group.
+type GroupStrategy int
+
+const (
+	// Average predicts the mean of the members' predicted ratings.
+	Average GroupStrategy = iota
+
+	// LeastMisery predicts the lowest of the members' predicted
+	// ratings, so the group avoids items any member would dislike.
+	LeastMisery
+
+	// MostPleasure predicts the highest of the members' predicted
+	// ratings.
+	MostPleasure
+)
+
+// PredictGroup returns predicted ratings for a group of users, e.g.,
+// friends choosing what to watch together. Each member's ratings are
+// predicted as by Predict, and the predictions for each item are
+// combined according to strategy.
+//
+// Only items that can be predicted for every member are returned, so
+// items any member has rated, or that cannot be predicted from some
+// member's ratings, are omitted.
+func (s1 *S1) PredictGroup(urs []UserRatings, strategy GroupStrategy) map[int]float64 {
+	if len(urs) == 0 {
+		return map[int]float64{}
+	}
+
+	group := s1.Predict(urs[0])
+	for _, ur := range urs[1:] {
+		p := s1.Predict(ur)
+		for i, gr := range group {
+			r, ok := p[i]
+			if !ok {
+				delete(group, i)
+				continue
+			}
+
+			switch strategy {
+			case LeastMisery:
+				group[i] = math.Min(gr, r)
+			case MostPleasure:
+				group[i] = math.Max(gr, r)
+			default:
+				group[i] = gr + r
+			}
+		}
+	}
+
+	if strategy != LeastMisery && strategy != MostPleasure {
+		for i := range group {
+			group[i] /= float64(len(urs))
+		}
+	}
+	return group
+}
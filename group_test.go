@@ -0,0 +1,31 @@
+package slopeone
+
+import "testing"
+
+func TestPredictGroup(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{{1: 1, 2: 3, 3: 2, 4: 5}, {5: 1, 2: 1}})
+	members := []UserRatings{{1: 1}, {1: 3}, {5: 2, 1: 2}}
+
+	// Item 2 is predicted 3, 5 and 3 for the members, item 3 is
+	// predicted 2, 4 and 3, and item 4 is predicted 5, 7 and 6.
+	tests := []struct {
+		strategy GroupStrategy
+		want     map[int]float64
+	}{
+		{Average, map[int]float64{2: 11.0 / 3, 3: 3, 4: 6}},
+		{LeastMisery, map[int]float64{2: 3, 3: 2, 4: 5}},
+		{MostPleasure, map[int]float64{2: 5, 3: 4, 4: 7}},
+	}
+	for _, tt := range tests {
+		if got := s1.PredictGroup(members, tt.strategy); !samePredictions(got, tt.want) {
+			t.Errorf("strategy %v: got %v, want %v", tt.strategy, got, tt.want)
+		}
+	}
+
+	// Only item 2 can be predicted from item 5 alone.
+	members = append(members, UserRatings{5: 2})
+	if got := s1.PredictGroup(members, Average); len(got) != 1 || !near(got[2], 3.25) {
+		t.Errorf("got %v, want only item 2", got)
+	}
+}
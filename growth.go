@@ -0,0 +1,123 @@
+package slopeone
+
+// Approximate memory costs of the maps an S1 is held in, used to
+// estimate its size.
+const (
+	// mapEntryBytes is the size of an entry of a map from int to a
+	// value of 8 bytes, including the map's unused capacity.
+	mapEntryBytes = 24
+
+	// mapBytes is the fixed size of a non-empty map.
+	mapBytes = 64
+)
+
+// GrowthEstimate describes how training would grow an S1.
+type GrowthEstimate struct {
+	// NewItems is the number of items that would be added.
+	NewItems int
+
+	// NewPairs is the number of distinct pairs of items that would be
+	// added, in the same units as NumPairs.
+	NewPairs int
+
+	// Bytes approximates the memory the new items and pairs would take
+	// up.
+	Bytes int64
+}
+
+// EstimateGrowth estimates how adding users with AddRatings would grow
+// the S1, e.g., for capacity planning before a large ingest, without
+// modifying the S1.
+//
+// If a limit has been set with SetMaxPairs, NewPairs is capped at the
+// number of pairs the limit leaves room for. Bytes does not include the
+// memory of the individual differences retained when trimming is
+// enabled, which grows with every rating rather than with new pairs.
+func (s1 *S1) EstimateGrowth(users []UserRatings) GrowthEstimate {
+	items := make(map[int]bool)
+	pairs := make(map[[2]int]bool)
+	for _, ur := range users {
+		for i1 := range ur {
+			if _, ok := s1.f[i1]; !ok {
+				items[i1] = true
+			}
+			for i2 := range ur {
+				if i1 >= i2 {
+					continue
+				}
+				if _, ok := s1.f[i1][i2]; !ok {
+					pairs[[2]int{i1, i2}] = true
+				}
+			}
+		}
+	}
+
+	g := GrowthEstimate{NewItems: len(items), NewPairs: len(pairs)}
+	if s1.maxPairs > 0 {
+		room := s1.maxPairs - s1.NumPairs()
+		if room < 0 {
+			room = 0
+		}
+		if g.NewPairs > room {
+			g.NewPairs = room
+		}
+	}
+
+	// Each item's row has a self pair, and each pair an entry in the
+	// rows of both of its items, in every map the S1 maintains.
+	entries := int64(2*g.NewPairs + g.NewItems)
+	g.Bytes = s1.pairMaps() * (entries*mapEntryBytes + int64(g.NewItems)*mapBytes)
+	g.Bytes += int64(g.NewItems) * 2 * mapEntryBytes // counts and sums
+	return g
+}
+
+// EstimateMemory estimates the memory, in bytes, that the S1's model
+// takes up: the maps holding the rating differences and frequencies of
+// every pair of items, along with the weights, squared differences and
+// compensations held when those are enabled, and each item's rating
+// count and sum. Keys, values and the maps' own overheads are all
+// accounted for, as is the memory of the individual differences
+// retained when trimming is enabled.
+//
+// It is an estimate, from the typical cost of a map entry, not a
+// measurement: the true size depends on how full the maps are and on
+// the Go runtime. It is consistent with EstimateGrowth, so that,
+// unless trimming is enabled, the estimate after training is that
+// before it plus the growth estimated for the training.
+func (s1 *S1) EstimateMemory() int64 {
+	var entries int64
+	for _, row := range s1.f {
+		entries += int64(len(row))
+	}
+	rows := int64(len(s1.f))
+	b := s1.pairMaps() * (entries*mapEntryBytes + rows*mapBytes)
+	b += int64(len(s1.counts)) * 2 * mapEntryBytes
+
+	if s1.samples != nil {
+		// Each retained difference is 8 bytes, and each pair's slice
+		// of them has a header of 24 bytes rather than an 8 byte value.
+		b += entries*mapEntryBytes + rows*mapBytes
+		for _, row := range s1.samples {
+			for _, samples := range row {
+				b += 16 + 8*int64(cap(samples))
+			}
+		}
+	}
+	return b
+}
+
+// pairMaps returns the number of maps of pairs of items, each with an
+// entry for every pair, that the S1 maintains.
+func (s1 *S1) pairMaps() int64 {
+	maps := int64(2)
+	if s1.w != nil {
+		maps++
+	}
+	if s1.sq != nil {
+		maps++
+	}
+	if s1.comp != nil {
+		maps++
+	}
+	return maps
+}
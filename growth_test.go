@@ -0,0 +1,68 @@
+package slopeone
+
+import "testing"
+
+func TestEstimateGrowth(t *testing.T) {
+	data := GenerateRatings(60, 30, 0.3, 11)
+	s1 := NewS1()
+	s1.AddRatings(data[:30])
+
+	est := s1.EstimateGrowth(data[30:])
+	pairs, items := s1.NumPairs(), len(s1.d)
+	s1.AddRatings(data[30:])
+	if got := s1.NumPairs() - pairs; est.NewPairs != got {
+		t.Errorf("got %d new pairs, estimated %d", got, est.NewPairs)
+	}
+	if got := len(s1.d) - items; est.NewItems != got {
+		t.Errorf("got %d new items, estimated %d", got, est.NewItems)
+	}
+	if est.NewPairs > 0 && est.Bytes <= 0 {
+		t.Errorf("estimated %d bytes for %d new pairs", est.Bytes, est.NewPairs)
+	}
+
+	empty := NewS1()
+	users := []UserRatings{{1: 1, 2: 2, 3: 3}, {1: 2, 2: 2}}
+	if est := empty.EstimateGrowth(users); est.NewItems != 3 || est.NewPairs != 3 {
+		t.Errorf("got %+v, want 3 items and 3 pairs", est)
+	}
+	if empty.NumPairs() != 0 {
+		t.Error("EstimateGrowth modified the S1")
+	}
+	empty.SetMaxPairs(2)
+	if est := empty.EstimateGrowth(users); est.NewPairs != 2 {
+		t.Errorf("limited: got %d pairs, want 2", est.NewPairs)
+	}
+}
+
+func TestEstimateMemory(t *testing.T) {
+	s1 := NewS1()
+	if got := s1.EstimateMemory(); got != 0 {
+		t.Errorf("empty: got %d bytes, want 0", got)
+	}
+
+	// The estimate grows by the estimated growth of each batch, and so
+	// linearly in the number of pairs.
+	data := GenerateRatings(50, 200, 0.05, 1)
+	var perPair int64
+	for k := 0; k < 5; k++ {
+		batch := data[k*10 : k*10+10]
+		g := s1.EstimateGrowth(batch)
+		before := s1.EstimateMemory()
+		s1.AddRatings(batch)
+		if got, want := s1.EstimateMemory(), before+g.Bytes; got != want {
+			t.Errorf("batch %d: got %d bytes, want %d", k, got, want)
+		}
+		per := s1.EstimateMemory() / int64(s1.NumPairs())
+		if perPair != 0 && (per < perPair/2 || per > perPair*2) {
+			t.Errorf("batch %d: got %d bytes per pair, want about %d", k, per, perPair)
+		}
+		perPair = per
+	}
+
+	trimmed := NewS1()
+	trimmed.SetTrimFraction(0.1)
+	trimmed.AddRatings(data)
+	if trimmed.EstimateMemory() <= s1.EstimateMemory() {
+		t.Error("the retained differences of a trimmed model were not counted")
+	}
+}
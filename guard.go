@@ -0,0 +1,20 @@
+package slopeone
+
+// PredictGuarded is like Predict, but also returns the number of items
+// whose predictions were dropped because they overflowed.
+//
+// A prediction overflows when the arithmetic it is made with does not
+// stay finite, e.g., when a client bug submits an enormous rating. Such
+// predictions are never returned by any method, rather than being
+// returned as infinite or NaN; PredictGuarded reports how many there
+// were, e.g., to alert on the bad input.
+func (s1 *S1) PredictGuarded(ur UserRatings) (map[int]float64, int) {
+	est := s1.estimates(ur, false)
+	var dropped int
+	for _, e := range est {
+		if e.overflowed() {
+			dropped++
+		}
+	}
+	return s1.finishAll(est), dropped
+}
@@ -0,0 +1,31 @@
+package slopeone
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPredictGuarded(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{{1: 3, 2: 4, 3: 2}, {4: 1, 2: 1}})
+
+	// Summing the predictions of item 2 from items 1 and 3 overflows.
+	ur := UserRatings{1: math.MaxFloat64, 3: math.MaxFloat64, 4: 1}
+	got, dropped := s1.PredictGuarded(ur)
+	if _, ok := got[2]; ok || dropped != 1 {
+		t.Errorf("got %v with %d dropped, want item 2 dropped", got, dropped)
+	}
+	if r, ok := s1.Predict(ur)[2]; ok {
+		t.Errorf("Predict: got %v for item 2, want it dropped", r)
+	}
+
+	// Clamping to a scale does not turn the overflow into a rating.
+	s1.SetRatingScale(1, 5)
+	if r, ok := s1.Predict(ur)[2]; ok {
+		t.Errorf("clamped: got %v for item 2, want it dropped", r)
+	}
+
+	if _, dropped := s1.PredictGuarded(UserRatings{1: math.Inf(1)}); dropped != 2 {
+		t.Errorf("infinite rating: got %d dropped, want 2", dropped)
+	}
+}
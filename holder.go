@@ -0,0 +1,45 @@
+package slopeone
+
+import "sync/atomic"
+
+// Holder holds the live S1 of a long-running server, allowing it to be
+// replaced with a retrained one without blocking predictions.
+//
+// The intended lifecycle is:
+//
+//  1. Train an S1 and Swap it into the Holder.
+//  2. Serve predictions with the Holder's Predict, from any number of
+//     goroutines.
+//  3. In the background, train a new S1, not the one held, and Swap it
+//     in once it is ready. Predictions already running finish with the
+//     previous S1; those started afterwards use the new one.
+//
+// An S1 must not be modified once it has been swapped into a Holder,
+// even after it has been replaced, as predictions may still be reading
+// it. The zero Holder holds no S1, and is ready for use. A Holder must
+// not be copied after first use.
+type Holder struct {
+	s1 atomic.Pointer[S1]
+}
+
+// Swap makes s1 the Holder's live S1, returning the previous one, or
+// nil if there was none.
+func (h *Holder) Swap(s1 *S1) *S1 {
+	return h.s1.Swap(s1)
+}
+
+// Load returns the Holder's live S1, or nil if there is none.
+func (h *Holder) Load() *S1 {
+	return h.s1.Load()
+}
+
+// Predict returns predicted ratings for items the provided user has not
+// yet rated, as S1.Predict does for the Holder's live S1. It returns an
+// empty map if the Holder holds no S1.
+func (h *Holder) Predict(ur UserRatings) map[int]float64 {
+	s1 := h.s1.Load()
+	if s1 == nil {
+		return make(map[int]float64)
+	}
+	return s1.Predict(ur)
+}
@@ -0,0 +1,45 @@
+package slopeone
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestHolder(t *testing.T) {
+	var h Holder
+	if got := h.Predict(UserRatings{1: 1}); len(got) != 0 {
+		t.Errorf("empty Holder: got %v, want no predictions", got)
+	}
+
+	// Run with -race to check that swapping never races with reads.
+	data := GenerateRatings(30, 10, 0.5, 1)
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					h.Predict(data[0])
+				}
+			}
+		}()
+	}
+
+	var last *S1
+	for k := 0; k < 20; k++ {
+		last = NewS1()
+		last.AddRatings(data[:k+5])
+		h.Swap(last)
+	}
+	close(stop)
+	wg.Wait()
+
+	if got, want := h.Predict(data[1]), last.Predict(data[1]); !samePredictions(got, want) {
+		t.Errorf("got %v, want the latest model's %v", got, want)
+	}
+}
@@ -0,0 +1,58 @@
+package slopeone
+
+import "math"
+
+// MostInformativeItem returns which of candidates the provided user
+// should be asked to rate next, e.g., for a "rate this to improve your
+// recommendations" prompt, along with its impact: how much rating it
+// would change their predictions. Candidates the user has already
+// rated are ignored. If there are no other candidates, item and impact
+// are 0.
+//
+// As the user's rating of a candidate is unknown, impact measures how
+// much of their predictions it would determine rather than how far
+// they would move. Rating candidate c adds a prediction made from c to
+// each item j that c has been rated together with, and the share of
+// j's prediction that it would make up is
+//
+//	w(j, c) / (W(j) + w(j, c))
+//
+// where w(j, c) is the weight of the pair of items in predictions and
+// W(j) the total weight of the predictions already made for j. An item
+// that cannot yet be predicted has a share of 1. A candidate's impact
+// is the sum of its shares over the items the user has not rated, so
+// candidates relevant to many items that are poorly predicted are the
+// most informative. Ties are broken by the order of candidates.
+func (s1 *S1) MostInformativeItem(ur UserRatings, candidates []int) (item int, impact float64) {
+	est := s1.estimates(ur, false)
+	sd := s1.spread(ur)
+
+	best := math.Inf(-1)
+	for _, c := range candidates {
+		if _, ok := ur[c]; ok {
+			continue
+		}
+
+		var total float64
+		for j := range s1.f[c] {
+			if _, ok := ur[j]; ok || j == c {
+				continue
+			}
+
+			// The rating does not affect the weight of the prediction;
+			// an infinite one is used so that it is not excluded by
+			// the source rating floor.
+			var e estimate
+			if s1.contribute(&e, j, c, math.Inf(1), sd); e.weight != 0 {
+				total += e.weight / (est[j].weight + e.weight)
+			}
+		}
+		if total > best {
+			item, best = c, total
+		}
+	}
+	if math.IsInf(best, -1) {
+		return 0, 0
+	}
+	return item, best
+}
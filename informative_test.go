@@ -0,0 +1,31 @@
+package slopeone
+
+import "testing"
+
+func TestMostInformativeItem(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{
+		{1: 3, 2: 4, 3: 2, 4: 5, 5: 1},
+		{1: 2, 6: 3},
+		{7: 2, 6: 1},
+	})
+
+	// Nothing can yet be predicted from item 9. Rating item 2 would
+	// determine the predictions of items 1, 3, 4 and 5 entirely, but
+	// rating item 7 only that of item 6.
+	item, impact := s1.MostInformativeItem(UserRatings{9: 1}, []int{7, 2, 9})
+	if item != 2 || !near(impact, 4) {
+		t.Errorf("got item %d with impact %v, want item 2 with 4", item, impact)
+	}
+
+	// Items 3, 4 and 5 are already predicted from item 1 with the
+	// same weight as item 2 would add, so it would make up half of each.
+	item, impact = s1.MostInformativeItem(UserRatings{1: 3}, []int{2})
+	if item != 2 || !near(impact, 1.5) {
+		t.Errorf("got item %d with impact %v, want item 2 with 1.5", item, impact)
+	}
+
+	if item, impact := s1.MostInformativeItem(UserRatings{9: 1}, []int{9}); item != 0 || impact != 0 {
+		t.Errorf("only rated candidates: got item %d with impact %v, want 0 and 0", item, impact)
+	}
+}
@@ -0,0 +1,77 @@
+package slopeone
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// PredictInterval returns an approximate 95% confidence interval,
+// [lo, hi], for the predicted rating of item for the provided user,
+// along with the prediction itself, mid, as returned by PredictItem.
+//
+// The interval is estimated by bootstrapping: the prediction from each
+// of the user's rated items is treated as an independent estimate of
+// the item's rating, and the estimates are resampled with replacement
+// samples times, with lo and hi being the 2.5th and 97.5th percentiles
+// of the resampled predictions. The interval is only as good as that
+// assumption of independence, and is degenerate when the prediction is
+// based on a single rated item. The resampling is driven by seed, so
+// the same seed always produces the same interval.
+//
+// All three values are NaN if the item cannot be predicted.
+func (s1 *S1) PredictInterval(ur UserRatings, item int, samples int, seed int64) (lo, mid, hi float64) {
+	return s1.PredictIntervalWithRand(ur, item, samples, rand.New(rand.NewSource(seed)))
+}
+
+// PredictIntervalWithRand is like PredictInterval, but draws the
+// resamples from rnd, so the interval is the same for a given state of
+// rnd.
+func (s1 *S1) PredictIntervalWithRand(ur UserRatings, item int, samples int, rnd *rand.Rand) (lo, mid, hi float64) {
+	mid, ok := s1.PredictItem(ur, item)
+	if !ok {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+
+	// The estimates are ordered by rated item, so that they are
+	// resampled identically for the same state of rnd.
+	rated := make([]int, 0, len(ur))
+	for i := range ur {
+		rated = append(rated, i)
+	}
+	sort.Ints(rated)
+
+	var parts []estimate
+	sd := s1.spread(ur)
+	for _, i := range rated {
+		var c estimate
+		if s1.contribute(&c, item, i, ur[i], sd); c.weight != 0 {
+			parts = append(parts, c)
+		}
+	}
+	if samples < 1 {
+		samples = 1
+	}
+
+	preds := make([]float64, 0, samples)
+	for k := 0; k < samples; k++ {
+		var e estimate
+		for range parts {
+			c := parts[rnd.Intn(len(parts))]
+			e.sum += c.sum
+			e.weight += c.weight
+			e.support += c.support
+			e.sources += c.sources
+			e.prior, e.hasPrior = c.prior, c.hasPrior
+		}
+		if r, ok := s1.finish(item, e); ok {
+			preds = append(preds, r)
+		}
+	}
+	if len(preds) == 0 {
+		return mid, mid, mid
+	}
+
+	sort.Float64s(preds)
+	return percentile(preds, 0.025), mid, percentile(preds, 0.975)
+}
@@ -0,0 +1,52 @@
+package slopeone
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPredictInterval(t *testing.T) {
+	data := GenerateRatings(200, 30, 0.3, 2)
+	s1 := NewS1()
+	s1.AddRatings(data)
+
+	ur := data[0]
+	item := -1
+	for i := 0; i < 30 && item < 0; i++ {
+		if _, ok := ur[i]; !ok {
+			item = i
+		}
+	}
+
+	lo, mid, hi := s1.PredictInterval(ur, item, 500, 7)
+	if want, _ := s1.PredictItem(ur, item); !near(mid, want) {
+		t.Errorf("got estimate %v, want %v", mid, want)
+	}
+	if !(lo <= mid && mid <= hi) || lo == hi {
+		t.Errorf("got interval [%v, %v] for estimate %v", lo, hi, mid)
+	}
+	if lo2, _, hi2 := s1.PredictInterval(ur, item, 500, 7); lo2 != lo || hi2 != hi {
+		t.Errorf("same seed: got [%v, %v], want [%v, %v]", lo2, hi2, lo, hi)
+	}
+
+	if lo, mid, hi := s1.PredictInterval(ur, 999, 10, 1); !math.IsNaN(lo) || !math.IsNaN(mid) || !math.IsNaN(hi) {
+		t.Errorf("unknown item: got [%v, %v, %v], want NaNs", lo, mid, hi)
+	}
+}
+
+func TestPredictIntervalWithRand(t *testing.T) {
+	data := GenerateRatings(20, 10, 0.5, 3)
+	s1 := NewS1()
+	s1.AddRatings(data)
+
+	item := 0
+	for _, ok := data[0][item]; ok; _, ok = data[0][item] {
+		item++
+	}
+	lo, mid, hi := s1.PredictIntervalWithRand(data[0], item, 50, rand.New(rand.NewSource(5)))
+	wlo, wmid, whi := s1.PredictInterval(data[0], item, 50, 5)
+	if math.IsNaN(mid) || lo != wlo || mid != wmid || hi != whi {
+		t.Errorf("got [%v, %v, %v], want [%v, %v, %v]", lo, mid, hi, wlo, wmid, whi)
+	}
+}
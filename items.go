@@ -0,0 +1,129 @@
+package slopeone
+
+// ItemRatingCount returns the number of users that have rated item.
+//
+// Unlike the number of times a pair of items has been rated together,
+// this counts every rating of the item, including those by users who
+// rated no other items.
+func (s1 *S1) ItemRatingCount(item int) int {
+	return s1.counts[item]
+}
+
+// ItemMean returns the mean rating of item, and false if it has no
+// ratings. Means are maintained as ratings are added and removed, so
+// ItemMean is cheap for any item.
+func (s1 *S1) ItemMean(item int) (float64, bool) {
+	n := s1.counts[item]
+	if n == 0 {
+		return 0, false
+	}
+	return s1.sums[item] / float64(n), true
+}
+
+// NumRatings returns the total number of individual ratings added to
+// the S1, less those that have since been removed.
+func (s1 *S1) NumRatings() int64 {
+	return s1.ratings
+}
+
+// MergeItems merges the item drop into the item keep, e.g., when two
+// item ids refer to the same product. The rating differences and
+// frequencies of drop to every other item are added to those of keep,
+// so that predictions for and from keep reflect the evidence for both,
+// and drop is removed.
+//
+// The relationship between keep and drop themselves is discarded.
+// Users that rated both are counted twice in keep's rating count, and
+// users added with UpsertUser that rated drop can no longer have their
+// ratings of drop exactly replaced.
+func (s1 *S1) MergeItems(keep, drop int) {
+	if keep == drop {
+		return
+	}
+	s1.unseed(keep)
+	s1.unseed(drop)
+
+	others := make([]int, 0, len(s1.f[drop]))
+	for j := range s1.f[drop] {
+		others = append(others, j)
+	}
+
+	for _, j := range others {
+		switch j {
+		case keep:
+			// Discarded below.
+		case drop:
+			s1.addRaw(keep, keep, s1.stats(drop, drop))
+		default:
+			s1.addRaw(keep, j, s1.stats(drop, j))
+			s1.addRaw(j, keep, s1.stats(j, drop))
+			s1.deletePair(j, drop)
+		}
+	}
+
+	for _, j := range others {
+		s1.deletePair(drop, j)
+	}
+	s1.deletePair(keep, drop)
+
+	if n, ok := s1.counts[drop]; ok {
+		s1.counts[keep] += n
+		s1.sums[keep] += s1.sums[drop]
+		delete(s1.counts, drop)
+		delete(s1.sums, drop)
+	}
+}
+
+// RemoveItem removes item from the S1, along with its rating
+// differences to every other item and its ratings, e.g., for an item
+// withdrawn from the catalog, so that it is neither predicted nor
+// predicted from. Ratings of item by users added with UpsertUser, and
+// those added with AddRatingsAt, are forgotten too, so that
+// re-submitting those users or expiring those ratings leaves the S1
+// consistent.
+//
+// Removal is irreversible: the item's history is discarded, and
+// ratings of it added later start afresh.
+func (s1 *S1) RemoveItem(item int) {
+	s1.unseed(item)
+	for _, ur := range s1.users {
+		s1.forget(ur, item)
+	}
+	for _, o := range s1.observed {
+		for _, ur := range o.users {
+			s1.forget(ur, item)
+		}
+	}
+
+	others := make([]int, 0, len(s1.f[item]))
+	for j := range s1.f[item] {
+		others = append(others, j)
+	}
+	for _, j := range others {
+		s1.deletePair(j, item)
+		s1.deletePair(item, j)
+	}
+
+	s1.ratings -= int64(s1.counts[item])
+	delete(s1.counts, item)
+	delete(s1.sums, item)
+}
+
+// forget deletes item from profile, a user's ratings the S1 retains so
+// that it can remove them later, such that removing the profile still
+// removes exactly what it contributes. A z-score normalised user's
+// rating differences all depend on each of their ratings, as do those
+// of a user whose influence is capped, so their contribution is
+// replaced by that of the profile without item.
+func (s1 *S1) forget(profile UserRatings, item int) {
+	if _, ok := profile[item]; !ok {
+		return
+	}
+	if s1.normalization == ZScore || s1.maxItemsPerUser > 0 {
+		s1.update(profile, -1)
+		delete(profile, item)
+		s1.update(profile, 1)
+		return
+	}
+	delete(profile, item)
+}
@@ -0,0 +1,153 @@
+package slopeone
+
+import (
+	"testing"
+	"time"
+)
+
+func TestItemRatingCount(t *testing.T) {
+	s1 := newFixture()
+	counts := make(map[int]int)
+	for _, ur := range fixture {
+		for i := range ur {
+			counts[i]++
+		}
+	}
+	for i, want := range counts {
+		if got := s1.ItemRatingCount(i); got != want {
+			t.Errorf("item %d: got %d, want %d", i, got, want)
+		}
+	}
+	if got := s1.ItemRatingCount(9); got != 0 {
+		t.Errorf("unknown item: got %d, want 0", got)
+	}
+
+	s1.UpsertUser(1, UserRatings{9: 1})
+	s1.UpsertUser(1, UserRatings{8: 1})
+	if got := s1.ItemRatingCount(9); got != 0 {
+		t.Errorf("replaced item: got %d, want 0", got)
+	}
+	if got := s1.ItemRatingCount(8); got != 1 {
+		t.Errorf("upserted item: got %d, want 1", got)
+	}
+}
+
+func TestNumRatings(t *testing.T) {
+	s1 := newFixture()
+	if got := s1.NumRatings(); got != 13 {
+		t.Fatalf("got %d ratings, want 13", got)
+	}
+	s1.RemoveRatings(fixture[:1])
+	if got := s1.NumRatings(); got != 10 {
+		t.Errorf("after removal: got %d ratings, want 10", got)
+	}
+	s1.UpsertUser(1, UserRatings{1: 1, 2: 2})
+	s1.UpsertUser(1, UserRatings{1: 1})
+	if got := s1.NumRatings(); got != 11 {
+		t.Errorf("after upserts: got %d ratings, want 11", got)
+	}
+
+	s := NewShardedS1(2)
+	s.AddRatings(fixture)
+	if got := s.NumRatings(); got != 13 {
+		t.Errorf("sharded: got %d ratings, want 13", got)
+	}
+}
+
+func TestMergeItems(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{{1: 1, 2: 3, 3: 2}, {1: 2, 20: 5, 3: 1}, {1: 4, 2: 4}})
+	s1.MergeItems(2, 20)
+
+	// Item 20 is a duplicate of item 2.
+	want := NewS1()
+	want.AddRatings([]UserRatings{{1: 1, 2: 3, 3: 2}, {1: 2, 2: 5, 3: 1}, {1: 4, 2: 4}})
+
+	for _, ur := range []UserRatings{{1: 3}, {3: 3}, {2: 1}} {
+		if got, w := s1.Predict(ur), want.Predict(ur); !samePredictions(got, w) {
+			t.Errorf("%v: got %v, want %v", ur, got, w)
+		}
+	}
+	if got, w := s1.NumPairs(), want.NumPairs(); got != w {
+		t.Errorf("got %d pairs, want %d", got, w)
+	}
+	if got := s1.ItemRatingCount(2); got != 3 {
+		t.Errorf("got %d ratings of item 2, want 3", got)
+	}
+	if got := s1.ItemRatingCount(20); got != 0 {
+		t.Errorf("got %d ratings of merged item 20, want 0", got)
+	}
+	if _, items := s1.DenseMatrix(); len(items) != 3 {
+		t.Errorf("got items %v, want [1 2 3]", items)
+	}
+}
+
+func TestItemMean(t *testing.T) {
+	s1 := newFixture()
+
+	// Compute the means from the ratings themselves.
+	check := func(users []UserRatings) {
+		t.Helper()
+		sums, counts := make(map[int]float64), make(map[int]int)
+		for _, ur := range users {
+			for i, r := range ur {
+				sums[i] += r
+				counts[i]++
+			}
+		}
+		for i, n := range counts {
+			if got, ok := s1.ItemMean(i); !ok || !near(got, sums[i]/float64(n)) {
+				t.Errorf("item %d: got %v, %v, want %v", i, got, ok, sums[i]/float64(n))
+			}
+		}
+		if _, ok := s1.ItemMean(-1); ok {
+			t.Error("unknown item has a mean")
+		}
+	}
+
+	check(fixture)
+	s1.RemoveRatings(fixture[3:])
+	check(fixture[:3])
+	if _, ok := s1.ItemMean(1); ok {
+		t.Error("item with its ratings removed has a mean")
+	}
+}
+
+func TestRemoveItem(t *testing.T) {
+	s1 := newFixture()
+	s1.UpsertUser(1, fixture[0])
+	s1.RemoveItem(2005)
+	if _, ok := s1.Predict(UserRatings{5513: 3})[2005]; ok {
+		t.Error("removed item 2005 was predicted")
+	}
+	s1.UpsertUser(1, UserRatings{5513: 1})
+	if got := s1.ItemRatingCount(2005); got != 0 {
+		t.Errorf("got %d ratings of item 2005, want 0", got)
+	}
+}
+
+func TestRemoveItemObserved(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	s1 := NewS1()
+	s1.AddRatingsAt([]UserRatings{{1: 3, 2: 4}, {1: 2, 3: 5}}, t0)
+	s1.RemoveItem(1)
+	s1.AddRatings([]UserRatings{{1: 4, 4: 1}})
+
+	// Expiring the earlier ratings removes only those of items 2 and 3,
+	// leaving the later ratings of item 1 alone.
+	s1.ExpireBefore(t0.Add(time.Hour))
+	if got := s1.NumRatings(); got != 2 {
+		t.Errorf("got %d ratings, want 2", got)
+	}
+	if got := s1.ItemRatingCount(1); got != 1 {
+		t.Errorf("got %d ratings of item 1, want 1", got)
+	}
+
+	ws := NewWindowedS1(1)
+	ws.AddRatings([]UserRatings{{1: 3, 2: 4}})
+	ws.RemoveItem(1)
+	ws.AddRatings([]UserRatings{{3: 5, 4: 1}})
+	if got := ws.NumRatings(); got != 2 {
+		t.Errorf("windowed: got %d ratings, want 2", got)
+	}
+}
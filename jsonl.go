@@ -0,0 +1,95 @@
+package slopeone
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// jsonlRating is a single line of JSON Lines ratings. The fields are
+// pointers so that missing fields can be detected.
+type jsonlRating struct {
+	User   *int     `json:"user"`
+	Item   *int     `json:"item"`
+	Rating *float64 `json:"rating"`
+}
+
+var errJSONLFields = errors.New("missing user, item or rating")
+
+// IngestJSONL adds the ratings read from r to the S1, in JSON Lines
+// format, e.g., as emitted by an event pipeline, with one rating per
+// line:
+//
+//	{"user":1,"item":42,"rating":4.5}
+//
+// Other fields are ignored, as are blank lines. As with IngestRows,
+// lines are expected to be grouped by user: the ratings of each run of
+// consecutive lines with the same user are added as a single user, as
+// if by AddRatings, when the next user's lines begin.
+//
+// A line that is not such an object stops ingestion with an error
+// giving its line number; IngestJSONLSkipping skips such lines instead.
+// If an error occurs, the users whose lines were all read before it
+// remain added.
+func (s1 *S1) IngestJSONL(r io.Reader) error {
+	_, err := s1.ingestJSONL(r, false)
+	return err
+}
+
+// IngestJSONLSkipping is like IngestJSONL, but skips lines that are not
+// valid ratings, rather than stopping at them, and returns the number
+// of lines skipped. The returned error is only non-nil if r cannot be
+// read.
+func (s1 *S1) IngestJSONLSkipping(r io.Reader) (skipped int, err error) {
+	return s1.ingestJSONL(r, true)
+}
+
+// ingestJSONL reads ratings in JSON Lines format from r, skipping and
+// counting invalid lines if skip is true.
+func (s1 *S1) ingestJSONL(r io.Reader, skip bool) (int, error) {
+	var (
+		current int
+		user    UserRatings
+		skipped int
+	)
+	flush := func() {
+		if len(user) > 0 {
+			s1.AddRatings([]UserRatings{user})
+		}
+	}
+
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var v jsonlRating
+		err := json.Unmarshal(line, &v)
+		if err == nil && (v.User == nil || v.Item == nil || v.Rating == nil) {
+			err = errJSONLFields
+		}
+		if err != nil {
+			if skip {
+				skipped++
+				continue
+			}
+			return skipped, fmt.Errorf("slopeone: line %d: %w", n, err)
+		}
+
+		if user == nil || *v.User != current {
+			flush()
+			current, user = *v.User, make(UserRatings)
+		}
+		user[*v.Item] = *v.Rating
+	}
+	if err := sc.Err(); err != nil {
+		return skipped, err
+	}
+	flush()
+	return skipped, nil
+}
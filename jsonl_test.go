@@ -0,0 +1,42 @@
+package slopeone
+
+import (
+	"strings"
+	"testing"
+)
+
+// jsonlStream holds the first fixture user's ratings and one of the
+// second user's, along with a blank line, an unknown field, a
+// truncated line and a line without a rating.
+const jsonlStream = `{"user":1,"item":2005,"rating":2.4}
+{"user":1,"item":5513,"rating":1.3}
+
+{"user":1,"item":13035,"rating":2.0,"ts":5}
+{"user":2,"item":5513,"rating":4
+{"user":2,"item":5513}
+{"user":2,"item":5513,"rating":4}
+`
+
+func TestIngestJSONL(t *testing.T) {
+	s1 := NewS1()
+	err := s1.IngestJSONL(strings.NewReader(jsonlStream))
+	if err == nil || !strings.Contains(err.Error(), "line 5") {
+		t.Errorf("got error %v, want one for line 5", err)
+	}
+	if got := s1.NumRatings(); got != 0 {
+		t.Errorf("got %d ratings, want none after an error", got)
+	}
+}
+
+func TestIngestJSONLSkipping(t *testing.T) {
+	s1 := NewS1()
+	n, err := s1.IngestJSONLSkipping(strings.NewReader(jsonlStream))
+	if err != nil || n != 2 {
+		t.Fatalf("got %d lines skipped, %v, want 2, nil", n, err)
+	}
+	want := NewS1()
+	want.AddRatings([]UserRatings{fixture[0], {5513: 4}})
+	if s1.NumRatings() != want.NumRatings() || s1.Fingerprint() != want.Fingerprint() {
+		t.Errorf("got %d ratings, want %d", s1.NumRatings(), want.NumRatings())
+	}
+}
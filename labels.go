@@ -0,0 +1,128 @@
+package slopeone
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// LabeledPrediction is a predicted rating along with the label of the
+// band it falls into, e.g., "Highly Recommended".
+type LabeledPrediction struct {
+	Rating float64
+
+	// Label is the label whose threshold the rating meets, or empty if
+	// it meets none.
+	Label string
+}
+
+type labelThreshold struct {
+	label string
+	min   float64
+}
+
+// SetLabelThresholds sets the labels given to predictions by
+// PredictLabeled, along with the minimum rating for each, e.g.,
+//
+//	s1.SetLabelThresholds(map[string]float64{
+//		"Highly Recommended": 4.5,
+//		"Recommended":        3.5,
+//		"Not for you":        math.Inf(-1),
+//	})
+//
+// A prediction is given the label with the highest threshold that it
+// meets, i.e., that is less than or equal to it. If several labels
+// share that threshold, the first in lexical order is used. A
+// prediction that meets no threshold has no label.
+func (s1 *S1) SetLabelThresholds(thresholds map[string]float64) {
+	labels := make([]labelThreshold, 0, len(thresholds))
+	for label, min := range thresholds {
+		labels = append(labels, labelThreshold{label, min})
+	}
+	sort.Slice(labels, func(a, b int) bool {
+		if labels[a].min != labels[b].min {
+			return labels[a].min > labels[b].min
+		}
+		return labels[a].label < labels[b].label
+	})
+	s1.labels = labels
+}
+
+// PredictLabeled is like Predict, but also labels each prediction
+// according to the thresholds set with SetLabelThresholds.
+func (s1 *S1) PredictLabeled(ur UserRatings) map[int]LabeledPrediction {
+	p := s1.Predict(ur)
+	out := make(map[int]LabeledPrediction, len(p))
+	for i, r := range p {
+		out[i] = LabeledPrediction{Rating: r, Label: s1.label(r)}
+	}
+	return out
+}
+
+// label returns the label of the predicted rating r.
+func (s1 *S1) label(r float64) string {
+	for _, lt := range s1.labels {
+		if r >= lt.min {
+			return lt.label
+		}
+	}
+	return ""
+}
+
+var errCategoryBoundaries = errors.New("slopeone: category boundaries must be increasing")
+
+// SetCategories sets the discrete categories, e.g., sentiments, that
+// predictions are mapped to by PredictCategorized. boundaries divide
+// the rating scale into len(boundaries)+1 ranges, and labels names
+// each range in ascending order, so there must be one more label than
+// there are boundaries: a rating below boundaries[0] is in labels[0],
+// and a rating from boundaries[k] up to, but excluding, boundaries[k+1]
+// is in labels[k+1]. A rating exactly on a boundary is therefore in
+// the category above it. For example,
+//
+//	s1.SetCategories([]float64{2.5, 4}, []string{"negative", "neutral", "positive"})
+//
+// places ratings below 2.5 in "negative", ratings from 2.5 up to 4 in
+// "neutral", and ratings of 4 or more in "positive".
+//
+// SetCategories returns an error, leaving the categories unchanged, if
+// the boundaries are not strictly increasing or the number of labels
+// does not match them.
+func (s1 *S1) SetCategories(boundaries []float64, labels []string) error {
+	if len(labels) != len(boundaries)+1 {
+		return fmt.Errorf("slopeone: %d category boundaries need %d labels, not %d", len(boundaries), len(boundaries)+1, len(labels))
+	}
+	for k, b := range boundaries {
+		if math.IsNaN(b) || (k > 0 && !(b > boundaries[k-1])) {
+			return errCategoryBoundaries
+		}
+	}
+	s1.boundaries = append([]float64(nil), boundaries...)
+	s1.categories = append([]string(nil), labels...)
+	return nil
+}
+
+// PredictCategorized is like Predict, but returns the category set
+// with SetCategories that each predicted rating falls into, rather
+// than the rating itself. Predictions are categorised after they are
+// clamped to their rating scale and rounded. If no categories have been
+// set, every prediction's category is empty.
+func (s1 *S1) PredictCategorized(ur UserRatings) map[int]string {
+	p := s1.Predict(ur)
+	out := make(map[int]string, len(p))
+	for i, r := range p {
+		out[i] = s1.category(r)
+	}
+	return out
+}
+
+// category returns the category of the predicted rating r.
+func (s1 *S1) category(r float64) string {
+	if len(s1.categories) == 0 {
+		return ""
+	}
+	return s1.categories[sort.Search(len(s1.boundaries), func(k int) bool {
+		return s1.boundaries[k] > r
+	})]
+}
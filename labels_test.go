@@ -0,0 +1,81 @@
+package slopeone
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPredictLabeled(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{{1: 3, 2: 4.5, 3: 3.5, 4: 1, 5: 4.49}})
+	s1.SetLabelThresholds(map[string]float64{"high": 4.5, "b": 3.5, "a": 3.5})
+
+	got := s1.PredictLabeled(UserRatings{1: 3})
+	want := map[int]string{
+		2: "high", // exactly at the threshold
+		3: "a",    // at a shared threshold, the first label lexically
+		4: "",     // below every threshold
+		5: "a",    // just below a threshold
+	}
+	for i, label := range want {
+		if got[i].Label != label {
+			t.Errorf("item %d, rated %v: got label %q, want %q", i, got[i].Rating, got[i].Label, label)
+		}
+	}
+	if got[2].Rating != 4.5 {
+		t.Errorf("got rating %v, want 4.5", got[2].Rating)
+	}
+
+	s1.SetLabelThresholds(map[string]float64{"any": math.Inf(-1)})
+	if got := s1.PredictLabeled(UserRatings{1: 3})[4].Label; got != "any" {
+		t.Errorf("got label %q, want %q", got, "any")
+	}
+}
+
+func TestPredictCategorized(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{{1: 3, 2: 3, 3: 4.5, 4: 1, 5: 2.5}})
+	ur := UserRatings{1: 3}
+	if got := s1.PredictCategorized(ur); len(got) != 4 || got[2] != "" {
+		t.Errorf("uncategorized: got %v, want empty categories", got)
+	}
+
+	if err := s1.SetCategories([]float64{2.5, 4}, []string{"neg", "neu", "pos"}); err != nil {
+		t.Fatal(err)
+	}
+	got := s1.PredictCategorized(ur)
+	want := map[int]string{
+		2: "neu",
+		3: "pos",
+		4: "neg",
+		5: "neu", // exactly on a boundary
+	}
+	for i, c := range want {
+		if got[i] != c {
+			t.Errorf("item %d: got %q, want %q", i, got[i], c)
+		}
+	}
+
+	for _, c := range []struct {
+		boundaries []float64
+		labels     []string
+	}{
+		{[]float64{4, 2.5}, []string{"a", "b", "c"}},
+		{[]float64{2, 2}, []string{"a", "b", "c"}},
+		{[]float64{2}, []string{"a"}},
+	} {
+		if err := s1.SetCategories(c.boundaries, c.labels); err == nil {
+			t.Errorf("%v, %q: got nil error", c.boundaries, c.labels)
+		}
+	}
+	if got := s1.PredictCategorized(ur)[3]; got != "pos" {
+		t.Errorf("after errors: got %q, want the categories unchanged", got)
+	}
+
+	if err := s1.SetCategories(nil, []string{"all"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := s1.PredictCategorized(ur)[4]; got != "all" {
+		t.Errorf("one category: got %q, want %q", got, "all")
+	}
+}
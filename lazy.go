@@ -0,0 +1,160 @@
+package slopeone
+
+import (
+	"container/list"
+	"io"
+	"os"
+	"sync"
+)
+
+// lazyCacheItems is the default number of items whose pairs a LazyS1
+// keeps in memory.
+const lazyCacheItems = 1024
+
+// LazyS1 is a read-only Slope One model that reads a model file written
+// with WriteMapped on demand, for models too large to hold in memory.
+//
+// Only the file's item index is read when it is opened. The pairs of
+// an item are read from the file when a prediction is made from a
+// rating of that item, and kept in memory for the most recently used
+// items only, so memory use is bounded by the size of the index and
+// the cache. A LazyS1 is safe for concurrent use. It must be closed
+// when no longer needed, after which it must not be used.
+type LazyS1 struct {
+	f      *os.File
+	items  []byte
+	pairs  int64 // offset of the pair entries in the file
+	mu     sync.Mutex
+	size   int
+	lru    *list.List // of *lazyBlock, most recently used first
+	blocks map[int]*list.Element
+}
+
+// lazyBlock holds the pair entries of an item.
+type lazyBlock struct {
+	item  int
+	pairs []byte
+}
+
+// OpenLazy opens the model file at path, which must have been written
+// with WriteMapped, for reading on demand. The pairs of up to 1024
+// items are kept in memory; SetCacheSize changes this.
+func OpenLazy(path string) (*LazyS1, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	l, err := newLazyS1(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// newLazyS1 validates the header and item index of f and returns a
+// *LazyS1 reading from it.
+func newLazyS1(f *os.File) (*LazyS1, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, mappedHeaderSize)
+	if _, err := io.ReadFull(f, header); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, corrupt("mapped model file is too short")
+		}
+		return nil, err
+	}
+	nItems, nPairs, err := parseMappedHeader(header, fi.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]byte, nItems*mappedItemSize)
+	if _, err := io.ReadFull(f, items); err != nil {
+		return nil, err
+	}
+	if err := checkMappedItems(items, nPairs); err != nil {
+		return nil, err
+	}
+
+	return &LazyS1{
+		f:      f,
+		items:  items,
+		pairs:  mappedHeaderSize + int64(len(items)),
+		size:   lazyCacheItems,
+		lru:    list.New(),
+		blocks: make(map[int]*list.Element),
+	}, nil
+}
+
+// SetCacheSize sets the number of items whose pairs are kept in
+// memory. Sizes less than 1 are treated as 1.
+func (l *LazyS1) SetCacheSize(n int) {
+	if n < 1 {
+		n = 1
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.size = n
+	l.trim()
+}
+
+// Close closes the model file.
+func (l *LazyS1) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lru.Init()
+	l.blocks = nil
+	return l.f.Close()
+}
+
+// trim removes the least recently used blocks until the cache is
+// within its size.
+func (l *LazyS1) trim() {
+	for l.lru.Len() > l.size {
+		b := l.lru.Remove(l.lru.Back()).(*lazyBlock)
+		delete(l.blocks, b.item)
+	}
+}
+
+// block returns the pair entries of item i, reading them from the file
+// if they are not in memory. It returns nil if i is not in the model.
+func (l *LazyS1) block(i int) ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if el, ok := l.blocks[i]; ok {
+		l.lru.MoveToFront(el)
+		return el.Value.(*lazyBlock).pairs, nil
+	}
+
+	start, end, ok := mappedRow(l.items, i)
+	if !ok {
+		return nil, nil
+	}
+	pairs := make([]byte, (end-start)*mappedPairSize)
+	if _, err := l.f.ReadAt(pairs, l.pairs+int64(start)*mappedPairSize); err != nil {
+		return nil, err
+	}
+	l.blocks[i] = l.lru.PushFront(&lazyBlock{i, pairs})
+	l.trim()
+	return pairs, nil
+}
+
+// Predict returns predicted ratings for items the provided user has not
+// yet rated, exactly as MappedS1.Predict does for the same file. Only
+// the pairs of the items the user has rated are read.
+func (l *LazyS1) Predict(ur UserRatings) (map[int]float64, error) {
+	return predictRows(ur, func(i int, fn func(gi int, sum float64, n int)) error {
+		pairs, err := l.block(i)
+		if err != nil {
+			return err
+		}
+		for k := 0; k < len(pairs)/mappedPairSize; k++ {
+			fn(mappedPair(pairs, k))
+		}
+		return nil
+	})
+}
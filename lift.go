@@ -0,0 +1,23 @@
+package slopeone
+
+// PredictLift returns, for each item Predict would predict for the
+// provided user, the lift of the prediction: how much higher it is than
+// the user's mean rating. A positive lift means the item is predicted
+// to please the user more than the items they have rated do on
+// average, regardless of how generously they rate.
+func (s1 *S1) PredictLift(ur UserRatings) map[int]float64 {
+	if len(ur) == 0 {
+		return make(map[int]float64)
+	}
+	var sum float64
+	for _, r := range ur {
+		sum += r
+	}
+	mean := sum / float64(len(ur))
+
+	p := s1.Predict(ur)
+	for i, r := range p {
+		p[i] = r - mean
+	}
+	return p
+}
@@ -0,0 +1,23 @@
+package slopeone
+
+import "testing"
+
+func TestPredictLift(t *testing.T) {
+	s1 := newFixture()
+	ur := UserRatings{2005: 2.4, 5513: 4, 359602: 5}
+	mean := (2.4 + 4 + 5) / 3
+
+	want := s1.Predict(ur)
+	got := s1.PredictLift(ur)
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want lifts for %v", got, want)
+	}
+	for i, r := range want {
+		if !near(got[i], r-mean) {
+			t.Errorf("item %d: got %v, want %v", i, got[i], r-mean)
+		}
+	}
+	if got := s1.PredictLift(nil); len(got) != 0 {
+		t.Errorf("no ratings: got %v", got)
+	}
+}
@@ -0,0 +1,193 @@
+package slopeone
+
+import "sort"
+
+// NumPairs returns the number of distinct pairs of items whose rating
+// differences the S1 holds.
+func (s1 *S1) NumPairs() int {
+	return s1.pairs / 2
+}
+
+// SetMaxPairs limits the number of distinct pairs of items the S1
+// holds to n. Whenever adding ratings takes the S1 over the limit, the
+// pairs with the lowest support, i.e., that have been rated together
+// the fewest times, are evicted until it is back within the limit. A
+// limit of 0 or less removes any limit.
+//
+// Eviction is lossy: the evicted pairs' history is discarded, so a
+// pair observed again starts afresh, and predictions that relied on
+// evicted pairs may be less accurate or no longer possible.
+func (s1 *S1) SetMaxPairs(n int) {
+	if n < 0 {
+		n = 0
+	}
+	s1.maxPairs = n
+	s1.evict()
+}
+
+// SetMaxItemsPerUser caps the influence of prolific users, who have
+// rated more than n items, on the rating differences between items.
+// Each rating difference of a user who has rated m > n items is given
+// a weight of
+//
+//	n / m
+//
+// so that, like a user who has rated n items, the user contributes a
+// total weight of n to the differences between each of their items and
+// the others. Differences are otherwise weighted as by
+// AddWeightedRatings, so the weights affect both the average
+// difference between a pair of items and how heavily that pair counts
+// towards a prediction, but not the pair's support. A limit of 0 or
+// less, the default, removes the cap.
+//
+// The cap applies to ratings subsequently added; ratings already in the
+// S1 keep their weights. Ratings must be removed with the same cap they
+// were added with, or the S1 is left meaningless.
+func (s1 *S1) SetMaxItemsPerUser(n int) {
+	if n < 0 {
+		n = 0
+	}
+	s1.maxItemsPerUser = n
+}
+
+// userWeight returns the weight of each rating difference of a user
+// who has rated m items.
+func (o *options) userWeight(m int) float64 {
+	if o.maxItemsPerUser == 0 || m <= o.maxItemsPerUser {
+		return 1
+	}
+	return float64(o.maxItemsPerUser) / float64(m)
+}
+
+// PruneBelowSupport removes every pair of items that has been rated
+// together fewer than n times, e.g., to shrink a model for serving when
+// such pairs would be ignored because of SetMinSupport anyway. Items
+// left without pairs to any other item are removed altogether. It
+// returns the number of distinct pairs removed.
+//
+// Pruning is irreversible: the history of the removed pairs is
+// discarded.
+func (s1 *S1) PruneBelowSupport(n int) int {
+	var removed int
+	for i1, freqs := range s1.f {
+		for i2, f := range freqs {
+			if i1 != i2 && f < n {
+				s1.deletePair(i1, i2)
+				if i1 < i2 {
+					removed++
+				}
+			}
+		}
+		if len(freqs) == 1 {
+			s1.deletePair(i1, i1)
+		}
+	}
+	return removed
+}
+
+// evict removes the pairs with the lowest support until the S1 is
+// within its pair limit.
+func (s1 *S1) evict() {
+	excess := s1.NumPairs() - s1.maxPairs
+	if s1.maxPairs == 0 || excess <= 0 {
+		return
+	}
+
+	type pair struct{ i1, i2, n int }
+	pairs := make([]pair, 0, s1.NumPairs())
+	for i1, freqs := range s1.f {
+		for i2, n := range freqs {
+			if i1 < i2 {
+				pairs = append(pairs, pair{i1, i2, n})
+			}
+		}
+	}
+
+	// Ties are broken on the items so eviction is deterministic.
+	sort.Slice(pairs, func(a, b int) bool {
+		if pairs[a].n != pairs[b].n {
+			return pairs[a].n < pairs[b].n
+		}
+		if pairs[a].i1 != pairs[b].i1 {
+			return pairs[a].i1 < pairs[b].i1
+		}
+		return pairs[a].i2 < pairs[b].i2
+	})
+
+	for _, p := range pairs[:excess] {
+		s1.deletePair(p.i1, p.i2)
+		s1.deletePair(p.i2, p.i1)
+	}
+}
+
+// Compact rebuilds the S1's internal maps from their current contents,
+// releasing the memory Go maps retain after many entries have been
+// removed, e.g., by PruneBelowSupport or RemoveRatings.
+//
+// Compact copies the entire model, so it is potentially expensive, and
+// temporarily needs up to twice the memory of the model.
+func (s1 *S1) Compact() {
+	s1.d = compactRows(s1.d)
+	s1.f = compactRows(s1.f)
+	if s1.w != nil {
+		s1.w = compactRows(s1.w)
+	}
+	if s1.sq != nil {
+		s1.sq = compactRows(s1.sq)
+	}
+	if s1.comp != nil {
+		s1.comp = compactRows(s1.comp)
+	}
+	if s1.samples != nil {
+		s1.samples = compactRows(s1.samples)
+	}
+
+	counts, sums := make(map[int]int, len(s1.counts)), make(map[int]float64, len(s1.sums))
+	for i, n := range s1.counts {
+		counts[i], sums[i] = n, s1.sums[i]
+	}
+	s1.counts, s1.sums = counts, sums
+}
+
+// SetAutoCompact makes the S1 run Compact after every n users' ratings
+// added or removed, e.g., by AddRatings, RemoveRatings or UpsertUser,
+// so that a long-running model that is continually updated does not
+// accumulate the memory retained by removed entries. An n of 0 or less,
+// the default, disables automatic compaction.
+//
+// Compaction runs as part of the call that reaches the n-th update,
+// rather than concurrently with it, as an S1 is not safe for concurrent
+// use: like any other write, that call must not run concurrently with
+// other uses of the S1. Compaction does not affect predictions.
+func (s1 *S1) SetAutoCompact(n int) {
+	if n < 0 {
+		n = 0
+	}
+	s1.autoCompact = n
+	s1.updates = 0
+}
+
+// updated records that n users' ratings have been added to or removed
+// from the S1, compacting it if automatic compaction is due.
+func (s1 *S1) updated(n int) {
+	if s1.autoCompact == 0 {
+		return
+	}
+	if s1.updates += n; s1.updates >= s1.autoCompact {
+		s1.Compact()
+		s1.updates = 0
+	}
+}
+
+// compactRows returns a freshly sized copy of m.
+func compactRows[V any](m map[int]map[int]V) map[int]map[int]V {
+	out := make(map[int]map[int]V, len(m))
+	for i, row := range m {
+		cp := make(map[int]V, len(row))
+		for j, v := range row {
+			cp[j] = v
+		}
+		out[i] = cp
+	}
+	return out
+}
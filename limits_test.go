@@ -0,0 +1,184 @@
+package slopeone
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestSetMaxPairs(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{
+		{1: 1, 2: 2},
+		{1: 1, 2: 2},
+		{1: 3, 2: 3, 3: 1},
+	})
+	if got := s1.NumPairs(); got != 3 {
+		t.Fatalf("got %d pairs, want 3", got)
+	}
+
+	// Items 1 and 2 have been rated together three times, and item 3
+	// with each of them only once.
+	s1.SetMaxPairs(1)
+	if got := s1.NumPairs(); got != 1 {
+		t.Fatalf("got %d pairs, want 1", got)
+	}
+	if _, ok := s1.deviation(1, 2); !ok {
+		t.Error("strongest pair (1, 2) was evicted")
+	}
+	for _, i := range []int{1, 2} {
+		if _, ok := s1.deviation(i, 3); ok {
+			t.Errorf("weak pair (%d, 3) was kept", i)
+		}
+	}
+	if got := s1.Predict(UserRatings{3: 1}); len(got) != 0 {
+		t.Errorf("got %v, want no predictions from item 3", got)
+	}
+
+	// Adding a new, weaker pair over the limit evicts it straight away.
+	s1.UpsertUser(1, UserRatings{5: 1, 6: 2})
+	if got := s1.NumPairs(); got != 1 {
+		t.Errorf("got %d pairs, want 1", got)
+	}
+	if _, ok := s1.deviation(1, 2); !ok {
+		t.Error("strongest pair (1, 2) was evicted")
+	}
+}
+
+func TestPruneBelowSupport(t *testing.T) {
+	data := GenerateRatings(100, 40, 0.1, 3)
+	s1 := NewS1()
+	s1.AddRatings(data)
+	s1.SetMinSupport(3)
+
+	var before []map[int]float64
+	for _, ur := range data[:10] {
+		before = append(before, s1.Predict(ur))
+	}
+
+	n := s1.NumPairs()
+	removed := s1.PruneBelowSupport(3)
+	if removed == 0 || s1.NumPairs() != n-removed {
+		t.Fatalf("removed %d of %d pairs, leaving %d", removed, n, s1.NumPairs())
+	}
+	for k, ur := range data[:10] {
+		if got := s1.Predict(ur); !samePredictions(got, before[k]) {
+			t.Errorf("user %d: got %v, want %v", k, got, before[k])
+		}
+	}
+
+	s1.PruneBelowSupport(1000)
+	if _, items := s1.DenseMatrix(); len(items) != 0 || s1.NumPairs() != 0 {
+		t.Errorf("got items %v left after pruning every pair", items)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	data := GenerateRatings(300, 200, 0.1, 3)
+	s1 := NewS1()
+	s1.AddRatings(data)
+	s1.RemoveRatings(data[:250])
+	s1.PruneBelowSupport(2)
+
+	before := make([]map[int]float64, 10)
+	for k, ur := range data[250:260] {
+		before[k] = s1.Predict(ur)
+	}
+	pairs := s1.NumPairs()
+	fp := s1.Fingerprint()
+
+	heap := func() uint64 {
+		var ms runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&ms)
+		return ms.HeapAlloc
+	}
+	h := heap()
+	s1.Compact()
+	runtime.KeepAlive(data)
+
+	for k, ur := range data[250:260] {
+		if got := s1.Predict(ur); !samePredictions(got, before[k]) {
+			t.Errorf("user %d: got %v, want %v", k, got, before[k])
+		}
+	}
+	if got := s1.NumPairs(); got != pairs {
+		t.Errorf("got %d pairs, want %d", got, pairs)
+	}
+	if got := s1.Fingerprint(); got != fp {
+		t.Errorf("got fingerprint %x, want %x", got, fp)
+	}
+	// The heap is shared with the rest of the process, so that it
+	// shrinks is only reported.
+	t.Logf("heap before Compact %d bytes, after %d", h, heap())
+}
+
+func TestSetAutoCompact(t *testing.T) {
+	plain, s1 := NewS1(), NewS1()
+	s1.SetAutoCompact(3)
+	var compactions int
+	for k := 0; k < 20; k++ {
+		before := s1.updates
+		for _, s := range []*S1{plain, s1} {
+			s.AddRatings(fixture)
+			if k%2 == 1 {
+				s.RemoveRatings(fixture[:2])
+			}
+			s.UpsertUser(k%3, fixture[k%4])
+		}
+		if s1.updates < before {
+			compactions++
+		}
+
+		if plain.Fingerprint() != s1.Fingerprint() {
+			t.Fatalf("round %d: fingerprints differ", k)
+		}
+		if got, want := s1.Predict(fixture[0]), plain.Predict(fixture[0]); !samePredictions(got, want) {
+			t.Fatalf("round %d: got %v, want %v", k, got, want)
+		}
+	}
+	if compactions == 0 {
+		t.Error("the S1 was never compacted")
+	}
+}
+
+func TestSetMaxItemsPerUser(t *testing.T) {
+	prolific := UserRatings{1: 5, 2: 1, 3: 3, 4: 3, 5: 3, 6: 3, 7: 3, 8: 3}
+	normal := UserRatings{1: 2, 2: 4}
+	plain, capped := NewS1(), NewS1()
+	capped.SetMaxItemsPerUser(2)
+	for _, s := range []*S1{plain, capped} {
+		s.AddRatings([]UserRatings{prolific, normal})
+	}
+
+	// Item 2 is rated 4 below item 1 by the prolific user and 2 above it
+	// by the other. Capped, the prolific user's difference has a weight
+	// of 2 / 8, so item 2 is (-4*0.25 + 2) / 1.25 = 0.8 above item 1.
+	ur := UserRatings{1: 3}
+	if got := plain.Predict(ur)[2]; !near(got, 2) {
+		t.Errorf("plain: got %v, want 2", got)
+	}
+	if got := capped.Predict(ur)[2]; !near(got, 3.8) {
+		t.Errorf("capped: got %v, want 3.8", got)
+	}
+	if got := capped.ItemRatingCount(1); got != 2 {
+		t.Errorf("got %d ratings of item 1, want 2", got)
+	}
+	capped.RemoveRatings([]UserRatings{prolific})
+	if got := capped.Predict(ur)[2]; !near(got, 5) {
+		t.Errorf("after removal: got %v, want 5", got)
+	}
+
+	// Ratings added one at a time are weighted as if added together.
+	incremental, batch := NewS1(), NewS1()
+	incremental.SetMaxItemsPerUser(2)
+	batch.SetMaxItemsPerUser(2)
+	var stream []RatingEvent
+	for i, r := range prolific {
+		stream = append(stream, RatingEvent{User: 1, Item: i, Rating: r})
+	}
+	incremental.PrequentialEval(stream)
+	batch.AddRatings([]UserRatings{prolific})
+	if got, want := incremental.Predict(ur), batch.Predict(ur); !samePredictions(got, want) {
+		t.Errorf("incremental: got %v, want %v", got, want)
+	}
+}
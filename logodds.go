@@ -0,0 +1,48 @@
+package slopeone
+
+import "math"
+
+// logOddsEpsilon bounds ratings away from 0 and 1 in log-odds mode, as
+// their log-odds are infinite.
+const logOddsEpsilon = 0.01
+
+// SetLogOdds enables or disables log-odds mode, for binary feedback
+// such as liked / not liked encoded as 1 / 0. It must be set before any
+// ratings are added.
+//
+// In log-odds mode each rating r, first clamped to [0.01, 0.99], is
+// replaced by its log-odds
+//
+//	logit(r) = ln(r / (1 - r))
+//
+// before rating differences are calculated, so the differences, and
+// the predictions made from them, are in log-odds space. Each
+// prediction x is then converted back to a probability with the
+// sigmoid function
+//
+//	sigmoid(x) = 1 / (1 + e^-x)
+//
+// so predictions are always in (0, 1), rather than averages of 0s and
+// 1s. Ratings should be binary, or probabilities, for this to be
+// meaningful.
+func (s1 *S1) SetLogOdds(enabled bool) {
+	s1.logOdds = enabled
+	s1.gen++
+}
+
+// in returns rating r as it is used in the model's calculations.
+func (o *options) in(r float64) float64 {
+	if !o.logOdds {
+		return r
+	}
+	r = math.Max(logOddsEpsilon, math.Min(1-logOddsEpsilon, r))
+	return math.Log(r / (1 - r))
+}
+
+// out returns the rating x calculated by the model as a prediction.
+func (o *options) out(x float64) float64 {
+	if !o.logOdds {
+		return x
+	}
+	return 1 / (1 + math.Exp(-x))
+}
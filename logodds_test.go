@@ -0,0 +1,26 @@
+package slopeone
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLogOdds(t *testing.T) {
+	s1 := NewS1()
+	s1.SetLogOdds(true)
+	s1.AddRatings([]UserRatings{{1: 1, 2: 1}, {1: 1, 2: 0}, {1: 1, 2: 1}})
+
+	// Probabilities are clamped to [0.01, 0.99], so liking an item is a
+	// log-odds of L and disliking it -L. Item 2's deviation from item 1
+	// is (0 - 2L + 0) / 3, so its predicted log-odds is L - 2L/3.
+	L := math.Log(0.99 / 0.01)
+	want := 1 / (1 + math.Exp(-L/3))
+
+	ur := UserRatings{1: 1}
+	if got := s1.Predict(ur)[2]; !near(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, _ := s1.PredictItem(ur, 2); !near(got, want) {
+		t.Errorf("PredictItem: got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,309 @@
+package slopeone
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+)
+
+// The mapped model file has a flat, fixed layout so that it can be
+// memory-mapped and read in place. All values are little-endian.
+//
+//	header:  magic [8]byte, items uint64, pairs uint64
+//	items:   items * {item int64, offset uint64, count uint64}
+//	pairs:   pairs * {other int64, sum float64, freq int64}
+//
+// Item entries are sorted by item. Each item entry refers to count
+// consecutive pair entries, starting at pair index offset, sorted by
+// other. A pair entry holds the sum of rating differences between item
+// and other, and the number of times the difference was calculated.
+//
+// The final byte of the magic is the layout's version.
+const (
+	mappedMagic      = "S1MAP\x00\x00\x01"
+	mappedHeaderSize = 24
+	mappedItemSize   = 24
+	mappedPairSize   = 24
+)
+
+var errMappedUnsupported = errors.New("slopeone: models trained with these settings cannot be written in the mapped format")
+
+// WriteMapped writes the S1 to w in the flat layout read by OpenMapped.
+// Models trained with weighted ratings, in log-odds mode, with variance
+// weighting, with trimming or with z-score normalization cannot be
+// written.
+func (s1 *S1) WriteMapped(w io.Writer) error {
+	if s1.w != nil || s1.logOdds || s1.sq != nil || s1.samples != nil || s1.normalization == ZScore {
+		return errMappedUnsupported
+	}
+
+	items := make([]int, 0, len(s1.d))
+	var pairs int
+	for i, diffs := range s1.d {
+		items = append(items, i)
+		pairs += len(diffs)
+	}
+	sort.Ints(items)
+
+	bw := bufio.NewWriter(w)
+	buf := make([]byte, 24)
+	put := func(a, b, c uint64) error {
+		binary.LittleEndian.PutUint64(buf[0:], a)
+		binary.LittleEndian.PutUint64(buf[8:], b)
+		binary.LittleEndian.PutUint64(buf[16:], c)
+		_, err := bw.Write(buf)
+		return err
+	}
+
+	if _, err := bw.WriteString(mappedMagic); err != nil {
+		return err
+	}
+	binary.LittleEndian.PutUint64(buf[0:], uint64(len(items)))
+	binary.LittleEndian.PutUint64(buf[8:], uint64(pairs))
+	if _, err := bw.Write(buf[:16]); err != nil {
+		return err
+	}
+
+	var offset int
+	for _, i := range items {
+		if err := put(uint64(i), uint64(offset), uint64(len(s1.d[i]))); err != nil {
+			return err
+		}
+		offset += len(s1.d[i])
+	}
+
+	for _, i := range items {
+		others := make([]int, 0, len(s1.d[i]))
+		for j := range s1.d[i] {
+			others = append(others, j)
+		}
+		sort.Ints(others)
+
+		for _, j := range others {
+			if err := put(uint64(j), math.Float64bits(s1.d[i][j]), uint64(s1.f[i][j])); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// MappedS1 is a read-only Slope One model backed by a memory-mapped
+// model file, allowing many processes to share a single copy of the
+// model through the operating system's page cache.
+//
+// A MappedS1 cannot be trained. It must be closed when no longer
+// needed, after which it must not be used.
+type MappedS1 struct {
+	data  []byte
+	items []byte
+	pairs []byte
+	unmap func([]byte) error
+}
+
+// OpenMapped memory-maps the model file at path, which must have been
+// written with WriteMapped.
+func OpenMapped(path string) (*MappedS1, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fi.Size() < mappedHeaderSize {
+		return nil, corrupt("mapped model file is too short")
+	}
+	if fi.Size() > math.MaxInt {
+		return nil, corrupt("mapped model file is too large")
+	}
+
+	data, err := mmapFile(f, int(fi.Size()))
+	if err != nil {
+		return nil, err
+	}
+	m, err := newMappedS1(data)
+	if err != nil {
+		munmapFile(data)
+		return nil, err
+	}
+	m.unmap = munmapFile
+	return m, nil
+}
+
+// newMappedS1 validates data and returns a *MappedS1 reading from it.
+func newMappedS1(data []byte) (*MappedS1, error) {
+	nItems, nPairs, err := parseMappedHeader(data, int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	m := &MappedS1{data: data}
+	itemsEnd := mappedHeaderSize + int(nItems)*mappedItemSize
+	m.items = data[mappedHeaderSize:itemsEnd]
+	m.pairs = data[itemsEnd:]
+
+	if err := checkMappedItems(m.items, nPairs); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// parseMappedHeader validates the header of a mapped model file of the
+// given size, and returns the number of items and pairs it holds.
+func parseMappedHeader(header []byte, size int64) (nItems, nPairs uint64, err error) {
+	if len(header) < mappedHeaderSize || size < mappedHeaderSize {
+		return 0, 0, corrupt("mapped model file is too short")
+	}
+	if string(header[:7]) != mappedMagic[:7] {
+		return 0, 0, corrupt("not a mapped model file")
+	}
+	if header[7] != mappedMagic[7] {
+		return 0, 0, &DecodeError{
+			Kind:   ErrUnsupportedVersion,
+			Detail: fmt.Sprintf("mapped model file version %d", header[7]),
+		}
+	}
+	nItems = binary.LittleEndian.Uint64(header[8:])
+	nPairs = binary.LittleEndian.Uint64(header[16:])
+
+	rest := uint64(size - mappedHeaderSize)
+	if nItems > rest/mappedItemSize || nPairs > rest/mappedPairSize ||
+		nItems*mappedItemSize+nPairs*mappedPairSize != rest {
+		return 0, 0, corrupt("mapped model file has wrong size for %d items and %d pairs", nItems, nPairs)
+	}
+	return nItems, nPairs, nil
+}
+
+// checkMappedItems validates that the item entries in items refer only
+// to pairs within the nPairs pair entries.
+func checkMappedItems(items []byte, nPairs uint64) error {
+	for k := 0; k < len(items)/mappedItemSize; k++ {
+		_, offset, count := mappedItem(items, k)
+		if offset > nPairs || count > nPairs-offset {
+			return corrupt("mapped model item %d refers to pairs out of range", k)
+		}
+	}
+	return nil
+}
+
+// Close unmaps the model file.
+func (m *MappedS1) Close() error {
+	if m.unmap == nil {
+		return nil
+	}
+	err := m.unmap(m.data)
+	m.data, m.items, m.pairs, m.unmap = nil, nil, nil, nil
+	return err
+}
+
+// item returns the k-th item entry.
+func (m *MappedS1) item(k int) (item int, offset, count uint64) {
+	return mappedItem(m.items, k)
+}
+
+// pair returns the k-th pair entry.
+func (m *MappedS1) pair(k int) (other int, sum float64, freq int) {
+	return mappedPair(m.pairs, k)
+}
+
+// row returns the range of pair entries for item i, if i is in the
+// model.
+func (m *MappedS1) row(i int) (start, end int, ok bool) {
+	return mappedRow(m.items, i)
+}
+
+// mappedItem returns the k-th item entry of items.
+func mappedItem(items []byte, k int) (item int, offset, count uint64) {
+	e := items[k*mappedItemSize:]
+	return int(int64(binary.LittleEndian.Uint64(e))),
+		binary.LittleEndian.Uint64(e[8:]),
+		binary.LittleEndian.Uint64(e[16:])
+}
+
+// mappedPair returns the k-th pair entry of pairs.
+func mappedPair(pairs []byte, k int) (other int, sum float64, freq int) {
+	e := pairs[k*mappedPairSize:]
+	return int(int64(binary.LittleEndian.Uint64(e))),
+		math.Float64frombits(binary.LittleEndian.Uint64(e[8:])),
+		int(int64(binary.LittleEndian.Uint64(e[16:])))
+}
+
+// mappedRow returns the range of pair entries for item i, if i has an
+// entry in items.
+func mappedRow(items []byte, i int) (start, end int, ok bool) {
+	n := len(items) / mappedItemSize
+	k := sort.Search(n, func(k int) bool {
+		item, _, _ := mappedItem(items, k)
+		return item >= i
+	})
+	if k == n {
+		return 0, 0, false
+	}
+	item, offset, count := mappedItem(items, k)
+	if item != i {
+		return 0, 0, false
+	}
+	return int(offset), int(offset + count), true
+}
+
+// Predict returns predicted ratings for items the provided user has not
+// yet rated, based on the rating they provide for items they have
+// rated, exactly as S1.Predict does for the model the file was written
+// from with its default options.
+func (m *MappedS1) Predict(ur UserRatings) map[int]float64 {
+	p, _ := predictRows(ur, func(i int, fn func(gi int, sum float64, n int)) error {
+		start, end, ok := m.row(i)
+		if !ok {
+			return nil
+		}
+		for k := start; k < end; k++ {
+			fn(m.pair(k))
+		}
+		return nil
+	})
+	return p
+}
+
+// predictRows returns predicted ratings for items the provided user has
+// not yet rated, exactly as S1.Predict does with its default settings,
+// for the models that hold only the sum and number of the rating
+// differences between each pair of items.
+//
+// row calls fn for each item gi rated together with item i, with the
+// sum of the differences between the ratings of i and gi, and their
+// number. As the difference between gi and i is their negation, the
+// rows of the items the user has rated are the only ones needed. If row
+// returns an error, predictRows returns it.
+func predictRows(ur UserRatings, row func(i int, fn func(gi int, sum float64, n int)) error) (map[int]float64, error) {
+	p, f := make(map[int]float64), make(map[int]int)
+	for i, r := range ur {
+		err := row(i, func(gi int, sum float64, n int) {
+			if n == 0 || gi == i {
+				return
+			}
+			p[gi] += -sum + float64(n)*r
+			f[gi] += n
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range p {
+		if _, ok := ur[i]; ok {
+			delete(p, i)
+			continue
+		}
+		p[i] /= float64(f[i])
+	}
+	return p, nil
+}
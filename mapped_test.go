@@ -0,0 +1,87 @@
+package slopeone
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeMapped writes s1 in the mapped format to a new file, returning
+// its path.
+func writeMapped(t *testing.T, s1 *S1) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := s1.WriteMapped(&buf); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "model")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMappedPredict(t *testing.T) {
+	s1 := newFixture()
+	m, err := OpenMapped(writeMapped(t, s1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	for _, ur := range []UserRatings{
+		{2005: 2.0, 29074: 3.2},
+		{1: 5},
+		{77: 1},
+		{5513: 2, 2: 1},
+	} {
+		if got, want := m.Predict(ur), s1.Predict(ur); !samePredictions(got, want) {
+			t.Errorf("%v: got %v, want %v", ur, got, want)
+		}
+	}
+}
+
+func TestWriteMappedUnsupported(t *testing.T) {
+	s1 := NewS1()
+	s1.AddWeightedRatings([]map[int]WeightedRating{{1: {Value: 1, Weight: 2}, 2: {Value: 3, Weight: 1}}})
+	if err := s1.WriteMapped(&bytes.Buffer{}); err == nil {
+		t.Error("got nil error writing a weighted model")
+	}
+}
+
+func TestLazyPredict(t *testing.T) {
+	data := GenerateRatings(100, 200, 0.05, 4)
+	s1 := NewS1()
+	s1.AddRatings(data)
+	path := writeMapped(t, s1)
+
+	m, err := OpenMapped(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	l, err := OpenLazy(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	const size = 8
+	l.SetCacheSize(size)
+	for k, ur := range data[:20] {
+		got, err := l.Predict(ur)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := m.Predict(ur); !samePredictions(got, want) {
+			t.Errorf("user %d: got %v, want %v", k, got, want)
+		}
+		if want := s1.Predict(ur); !samePredictions(got, want) {
+			t.Errorf("user %d: got %v, want the in-memory %v", k, got, want)
+		}
+		if n := l.lru.Len(); n > size || len(l.blocks) != n {
+			t.Fatalf("user %d: got %d items' pairs in memory, want at most %d", k, n, size)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package slopeone
+
+import (
+	"math"
+	"sort"
+)
+
+// DenseMatrix returns the S1's average rating differences as a dense
+// matrix, for use with numerical libraries.
+//
+// items lists every item in the S1 in ascending order, and mat[i][j]
+// is the average difference between the ratings of items[i] and
+// items[j]. Entries for pairs of items that have never been rated
+// together are NaN.
+//
+// The matrix has an entry for every pair of items, so for a large,
+// sparse S1 it may need a great deal more memory than the S1 itself.
+func (s1 *S1) DenseMatrix() (mat [][]float64, items []int) {
+	items = make([]int, 0, len(s1.d))
+	for i := range s1.d {
+		items = append(items, i)
+	}
+	sort.Ints(items)
+
+	mat = make([][]float64, len(items))
+	for x, i1 := range items {
+		mat[x] = make([]float64, len(items))
+		for y, i2 := range items {
+			if dev, ok := s1.deviation(i1, i2); ok {
+				mat[x][y] = dev
+			} else {
+				mat[x][y] = math.NaN()
+			}
+		}
+	}
+	return mat, items
+}
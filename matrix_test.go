@@ -0,0 +1,31 @@
+package slopeone
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDenseMatrix(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{{3: 1, 1: 4}, {1: 2, 2: 5}})
+
+	mat, items := s1.DenseMatrix()
+	if len(items) != 3 || items[0] != 1 || items[1] != 2 || items[2] != 3 {
+		t.Fatalf("got items %v, want [1 2 3]", items)
+	}
+
+	nan := math.NaN()
+	want := [][]float64{
+		{0, -3, 3},
+		{3, 0, nan},
+		{-3, nan, 0},
+	}
+	for x := range want {
+		for y, w := range want[x] {
+			got := mat[x][y]
+			if math.IsNaN(w) && !math.IsNaN(got) || !math.IsNaN(w) && got != w {
+				t.Errorf("mat[%d][%d] (%d vs %d): got %v, want %v", x, y, items[x], items[y], got, w)
+			}
+		}
+	}
+}
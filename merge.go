@@ -0,0 +1,99 @@
+package slopeone
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// Merge adds the ratings that other was trained on to the S1, as if
+// they had been added to the S1 directly, e.g., to combine models
+// trained separately on different users. Both models must have been
+// trained with the same settings; the S1's settings are kept. other is
+// not modified.
+//
+// Merging is exact because every model holds the sums of its rating
+// differences, rather than their averages, whatever settings it was
+// trained with: the deviation of a pair of items is always its sum
+// divided by its weight, which is its frequency unless weighted
+// ratings were added, so the sums, frequencies and weights of each pair
+// are simply added. Pairs whose deviations are moving averages, as set
+// with SetEMAAlpha, hold the average multiplied by the weight, so they
+// are merged as an average of the two weighted by their weights.
+//
+// The differences of models normalised differently, e.g., one with
+// ZScore and one without, are in different units, which cannot be
+// reconciled without the users' original ratings, so such models must
+// not be merged. Users added with UpsertUser and item metadata are not
+// merged.
+func (s1 *S1) Merge(other *S1) {
+	if other.w != nil {
+		s1.initWeights()
+	}
+
+	rows := make([]int, 0, len(other.f))
+	for i := range other.f {
+		rows = append(rows, i)
+	}
+	sort.Ints(rows)
+
+	for _, i1 := range rows {
+		s1.unseed(i1)
+		for i2 := range other.f[i1] {
+			s1.addRaw(i1, i2, other.stats(i1, i2))
+		}
+	}
+	for i, n := range other.counts {
+		s1.counts[i] += n
+		s1.sums[i] += other.sums[i]
+	}
+	s1.ratings += other.ratings
+	s1.evict()
+}
+
+// NewS1Parallel returns an *S1 trained on users using up to workers
+// goroutines, or as many as can run simultaneously if workers is less
+// than 1.
+//
+// The items are split between the goroutines, each of which trains a
+// separate S1 on every user, in order, but only on the differences from
+// its own items. As each sum of rating differences is accumulated in
+// the same order as by AddRatings, and the goroutines' items are
+// disjoint, merging their S1s gives exactly the model, down to the last
+// bit of every sum, that training sequentially with AddRatings does.
+func NewS1Parallel(users []UserRatings, workers int) *S1 {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(users) {
+		workers = len(users)
+	}
+	if workers <= 1 {
+		s1 := NewS1()
+		s1.AddRatings(users)
+		return s1
+	}
+
+	chunks := make([]*S1, workers)
+	var wg sync.WaitGroup
+	for k := range chunks {
+		chunks[k] = NewS1()
+		wg.Add(1)
+		go func(s1 *S1, k int) {
+			defer wg.Done()
+			own := func(i int) bool {
+				return int(uint(i)%uint(workers)) == k
+			}
+			for _, ur := range users {
+				s1.updateRows(ur, 1, own)
+			}
+		}(chunks[k], k)
+	}
+	wg.Wait()
+
+	s1 := chunks[0]
+	for _, c := range chunks[1:] {
+		s1.Merge(c)
+	}
+	return s1
+}
@@ -0,0 +1,81 @@
+package slopeone
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestNewS1Parallel(t *testing.T) {
+	// Ratings with differences that are not exact in binary, so that
+	// summing them in any other order would round differently.
+	data := GenerateRatings(200, 30, 0.3, 7)
+	for k, ur := range data {
+		for i := range ur {
+			ur[i] += float64(k%7) * 0.01 * float64(i%3)
+		}
+	}
+	seq := NewS1()
+	seq.AddRatings(data)
+
+	for _, workers := range []int{0, 1, 3, 8, 1000} {
+		par := NewS1Parallel(data, workers)
+		if par.Fingerprint() != seq.Fingerprint() || par.NumRatings() != seq.NumRatings() || par.NumPairs() != seq.NumPairs() {
+			t.Errorf("%d workers: model differs from sequential training", workers)
+		}
+		if !reflect.DeepEqual(par.d, seq.d) || !reflect.DeepEqual(par.f, seq.f) || !reflect.DeepEqual(par.sums, seq.sums) {
+			t.Errorf("%d workers: sums differ from sequential training", workers)
+		}
+		// Predict itself sums in map order, so only the models, not the
+		// predictions, are identical to the last bit.
+		for k, ur := range data[:10] {
+			if got, want := par.Predict(ur), seq.Predict(ur); !samePredictions(got, want) {
+				t.Errorf("%d workers, user %d: got %v, want %v", workers, k, got, want)
+			}
+		}
+	}
+	if got := NewS1Parallel(nil, 4).NumPairs(); got != 0 {
+		t.Errorf("no users: got %d pairs", got)
+	}
+}
+
+// BenchmarkNewS1Parallel trains the same model with an increasing
+// number of workers; one worker trains sequentially.
+func BenchmarkNewS1Parallel(b *testing.B) {
+	data := GenerateRatings(2000, 500, 0.04, 1)
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for k := 0; k < b.N; k++ {
+				NewS1Parallel(data, workers)
+			}
+		})
+	}
+}
+
+func TestMergeTrainingModes(t *testing.T) {
+	for _, n := range []Normalization{MeanCentering, ZScore} {
+		a, b, all := NewS1(), NewS1(), NewS1()
+		for _, s := range []*S1{a, b, all} {
+			s.SetNormalization(n)
+		}
+		a.AddRatings(fixture[:2])
+		b.AddRatings(fixture[2:])
+		all.AddRatings(fixture)
+		a.Merge(b)
+		if got, want := a.Predict(fixture[1]), all.Predict(fixture[1]); !samePredictions(got, want) {
+			t.Errorf("normalization %v: got %v, want %v", n, got, want)
+		}
+	}
+
+	// Moving averages of 2, from one difference, and of 1, from two,
+	// are averaged by their weights.
+	a, b := NewS1(), NewS1()
+	a.SetEMAAlpha(0.5)
+	b.SetEMAAlpha(0.5)
+	a.AddRatings([]UserRatings{{1: 1, 2: 3}})
+	b.AddRatings([]UserRatings{{1: 1, 2: 2}, {1: 1, 2: 2}})
+	a.Merge(b)
+	if got := a.Predict(UserRatings{1: 1})[2]; !near(got, 1+4.0/3) {
+		t.Errorf("EMA: got %v, want %v", got, 1+4.0/3)
+	}
+}
@@ -0,0 +1,40 @@
+package slopeone
+
+// MetaRecommendation is a Recommendation along with the metadata of its
+// item.
+type MetaRecommendation struct {
+	Recommendation
+
+	// Meta is the metadata set for the item with SetItemMeta, or nil.
+	Meta any
+}
+
+// SetItemMeta attaches arbitrary metadata, such as a title or category,
+// to item, to be returned alongside its recommendations by
+// TopNWithMeta. Setting nil metadata removes it.
+//
+// Metadata is ignored by the algorithm, and is held only in memory: it
+// is not written by any of the model's encoders, such as WriteMapped
+// and MarshalProto, nor copied to models derived from the S1, such as
+// by ExtractSubModel.
+func (s1 *S1) SetItemMeta(item int, meta any) {
+	if meta == nil {
+		delete(s1.meta, item)
+		return
+	}
+	if s1.meta == nil {
+		s1.meta = make(map[int]any)
+	}
+	s1.meta[item] = meta
+}
+
+// TopNWithMeta is like TopN, but also returns the metadata of each
+// recommended item.
+func (s1 *S1) TopNWithMeta(ur UserRatings, n int) []MetaRecommendation {
+	recs := s1.TopN(ur, n)
+	out := make([]MetaRecommendation, len(recs))
+	for k, rec := range recs {
+		out[k] = MetaRecommendation{Recommendation: rec, Meta: s1.meta[rec.Item]}
+	}
+	return out
+}
@@ -0,0 +1,23 @@
+package slopeone
+
+import "testing"
+
+func TestTopNWithMeta(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{{1: 3, 2: 5, 3: 4}})
+	type info struct{ Title string }
+	s1.SetItemMeta(2, info{"two"})
+	s1.SetItemMeta(3, "three")
+	s1.SetItemMeta(3, nil)
+
+	got := s1.TopNWithMeta(UserRatings{1: 3}, 2)
+	if len(got) != 2 || got[0].Item != 2 || got[1].Item != 3 {
+		t.Fatalf("got %v, want items 2 and 3", got)
+	}
+	if m, ok := got[0].Meta.(info); !ok || m.Title != "two" {
+		t.Errorf("got metadata %v, want %v", got[0].Meta, info{"two"})
+	}
+	if got[1].Meta != nil {
+		t.Errorf("got cleared metadata %v, want nil", got[1].Meta)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package slopeone
+
+import (
+	"io"
+	"os"
+)
+
+// On platforms without mmap support the model file is read into
+// memory instead, so it is not shared between processes.
+
+func mmapFile(f *os.File, size int) ([]byte, error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func munmapFile(data []byte) error {
+	return nil
+}
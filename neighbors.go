@@ -0,0 +1,27 @@
+package slopeone
+
+// NeighborInfo is a predicted rating along with the number of the
+// user's rated items it was predicted from.
+type NeighborInfo struct {
+	Rating float64
+
+	// Neighbors is the number of the user's rated items with a pair
+	// to the predicted item that contributed to the prediction, as
+	// counted by SetMinContributors.
+	Neighbors int
+}
+
+// PredictWithNeighborCount is like Predict, but also reports how many
+// of the user's rated items each prediction was made from, e.g., to
+// see how far settings such as SetMinSupport and SetSourceRatingFloor
+// narrow the evidence predictions are based on.
+func (s1 *S1) PredictWithNeighborCount(ur UserRatings) map[int]NeighborInfo {
+	est := s1.estimates(ur, false)
+	out := make(map[int]NeighborInfo, len(est))
+	for i, e := range est {
+		if r, ok := s1.finish(i, e); ok {
+			out[i] = NeighborInfo{Rating: r, Neighbors: e.sources}
+		}
+	}
+	return out
+}
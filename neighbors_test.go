@@ -0,0 +1,32 @@
+package slopeone
+
+import "testing"
+
+func TestPredictWithNeighborCount(t *testing.T) {
+	s1 := newFixture()
+	ur := UserRatings{2005: 3, 5513: 2, 1: 4}
+
+	want := s1.Predict(ur)
+	got := s1.PredictWithNeighborCount(ur)
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i, r := range want {
+		if !near(got[i].Rating, r) {
+			t.Errorf("item %d: got %v, want %v", i, got[i].Rating, r)
+		}
+	}
+	// Each of these items was co-rated with two of the user's items:
+	// 13035 with 2005 and 5513, 2 with 1 and 2005, 359602 with 2005
+	// and 5513.
+	for _, i := range []int{13035, 2, 359602} {
+		if n := got[i].Neighbors; n != 2 {
+			t.Errorf("item %d: got %d neighbors, want 2", i, n)
+		}
+	}
+
+	s1.SetMinContributors(3)
+	if got := s1.PredictWithNeighborCount(ur); len(got) != 0 {
+		t.Errorf("got %v, want no predictions from three neighbors", got)
+	}
+}
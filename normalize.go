@@ -0,0 +1,84 @@
+package slopeone
+
+import "math"
+
+// Normalization is a way of adjusting each user's ratings before their
+// rating differences are calculated, so that users who use the rating
+// scale differently are comparable.
+type Normalization int
+
+const (
+	// NoNormalization uses ratings as they are. It is the default.
+	NoNormalization Normalization = iota
+
+	// MeanCentering subtracts the user's mean rating from each of
+	// their ratings. As the mean cancels out of every rating
+	// difference, mean-centering alone does not change predictions.
+	MeanCentering
+
+	// ZScore subtracts the user's mean rating from each of their
+	// ratings, and divides the result by the standard deviation of
+	// their ratings, so that a user who uses the full rating scale and
+	// one whose ratings cluster near its middle are comparable.
+	ZScore
+)
+
+// SetNormalization sets how each user's ratings are normalised. It must
+// be set before any ratings are added.
+//
+// Rating differences are calculated between a user's normalised
+// ratings, and predictions are made in normalised space and converted
+// back with the statistics of the user they are made for. With ZScore,
+// a user's prediction of item j from their rating r of item i is
+//
+//	r + stddev * dev(j, i)
+//
+// where stddev is the standard deviation of the user's ratings and
+// dev(j, i) the average normalised rating difference between j and i.
+// The standard deviation of a user with fewer than two ratings, or
+// whose ratings are all the same, is taken to be 1, i.e., their ratings
+// are not scaled.
+//
+// In log-odds mode ratings are normalised after conversion to
+// log-odds.
+func (s1 *S1) SetNormalization(n Normalization) {
+	s1.normalization = n
+	s1.gen++
+}
+
+// spread returns the standard deviation of the user's ratings ur that
+// their rating differences are scaled by, which is 1 unless the
+// normalization is ZScore.
+func (o *options) spread(ur UserRatings) float64 {
+	if o.normalization != ZScore {
+		return 1
+	}
+	ratings := make([]float64, 0, len(ur))
+	for _, r := range ur {
+		ratings = append(ratings, r)
+	}
+	return o.spreadOf(ratings)
+}
+
+// spreadOf is like spread, for a user's ratings held in a slice.
+func (o *options) spreadOf(ratings []float64) float64 {
+	if o.normalization != ZScore || len(ratings) < 2 {
+		return 1
+	}
+
+	var sum float64
+	for _, r := range ratings {
+		sum += o.in(r)
+	}
+	mean := sum / float64(len(ratings))
+
+	var sq float64
+	for _, r := range ratings {
+		d := o.in(r) - mean
+		sq += d * d
+	}
+	if sd := math.Sqrt(sq / float64(len(ratings))); sd > 0 {
+		return sd
+	}
+	return 1
+}
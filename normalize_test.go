@@ -0,0 +1,63 @@
+package slopeone
+
+import "testing"
+
+func TestZScore(t *testing.T) {
+	// Both users' ratings have z-scores of -1 and 1, so item 2 is two
+	// standard deviations above item 1. Mean-centred, the users rate
+	// it 4 and 1 above item 1, 2.5 on average, as without normalization.
+	users := []UserRatings{{1: 1, 2: 5}, {1: 2.5, 2: 3.5}}
+	newModel := func(n Normalization) *S1 {
+		s1 := NewS1()
+		s1.SetNormalization(n)
+		s1.AddRatings(users)
+		return s1
+	}
+	z, m := newModel(ZScore), newModel(MeanCentering)
+
+	// The user's ratings have a mean of 3 and a standard deviation of
+	// 1, so item 1's rating of 2 is a z-score of -1.
+	ur := UserRatings{1: 2, 3: 4}
+	if got := z.Predict(ur)[2]; !near(got, 4) {
+		t.Errorf("z-score: got %v, want 4", got)
+	}
+	if got := m.Predict(ur)[2]; !near(got, 4.5) {
+		t.Errorf("mean-centred: got %v, want 4.5", got)
+	}
+
+	if got, _ := z.PredictItem(ur, 2); !near(got, 4) {
+		t.Errorf("PredictItem: got %v, want 4", got)
+	}
+	if got := z.Freeze().Predict(ur)[2]; !near(got, 4) {
+		t.Errorf("frozen: got %v, want 4", got)
+	}
+	ps := z.NewSession()
+	ps.AddRating(1, 2)
+	ps.AddRating(3, 4)
+	if got, ok := ps.Get(2); !ok || !near(got, 4) {
+		t.Errorf("session: got %v, %v, want 4", got, ok)
+	}
+}
+
+func TestZScoreIncremental(t *testing.T) {
+	users := []UserRatings{{1: 1, 2: 5}, {1: 2.5, 2: 3.5}, {1: 3, 2: 3, 3: 4}}
+	batch := NewS1()
+	batch.SetNormalization(ZScore)
+	batch.AddRatings(users)
+
+	// PrequentialEval adds each user's ratings one at a time.
+	var stream []RatingEvent
+	for u, ur := range users {
+		for i, r := range ur {
+			stream = append(stream, RatingEvent{User: u, Item: i, Rating: r})
+		}
+	}
+	incremental := NewS1()
+	incremental.SetNormalization(ZScore)
+	incremental.PrequentialEval(stream)
+
+	ur := UserRatings{1: 2, 3: 4}
+	if got, want := incremental.Predict(ur), batch.Predict(ur); !samePredictions(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
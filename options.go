@@ -0,0 +1,269 @@
+package slopeone
+
+import "math"
+
+// options holds the settings of an S1.
+type options struct {
+	// maxPairs is the maximum number of distinct item pairs retained,
+	// or 0 if there is no limit.
+	maxPairs int
+
+	// floor is the minimum rating of the user's items used as a source
+	// for predictions.
+	floor float64
+
+	// minSupport is the minimum number of times a pair of items must
+	// have been rated together to be used in predictions.
+	minSupport int
+
+	// minContributors is the minimum number of the user's rated items
+	// a prediction must be made from.
+	minContributors int
+
+	// logOdds is true if ratings are binary feedback, and rating
+	// differences and predictions are calculated in log-odds space.
+	logOdds bool
+
+	// min and max are the bounds of the rating scale predictions are
+	// clamped to, and scales holds the bounds for items on a different
+	// scale.
+	min, max float64
+	scales   map[int][2]float64
+
+	// from is per-call scratch state rather than a setting: it is nil
+	// in the S1's own options, and set only on the copy PredictScaled
+	// makes, to the scale predictions are mapped from onto [min, max].
+	from *[2]float64
+
+	// step is the granularity predictions are rounded to, or 0 if they
+	// are not rounded.
+	step float64
+
+	// observer, if not nil, is called with each prediction made, but
+	// not with those served from the cache.
+	observer func(item int, score float64, support int)
+
+	// weightFunc, if not nil, returns the weight of a prediction made
+	// from a pair of items with the given support.
+	weightFunc func(support int) float64
+
+	// normalization is how each user's ratings are normalised.
+	normalization Normalization
+
+	// labels holds the thresholds of the labels set with
+	// SetLabelThresholds, sorted by descending threshold and then by
+	// label.
+	labels []labelThreshold
+
+	// boundaries and categories are the categories set with
+	// SetCategories: ratings below boundaries[0] are in categories[0],
+	// and ratings of at least boundaries[k] in categories[k+1], up to
+	// the next boundary.
+	boundaries []float64
+	categories []string
+
+	// emaAlpha is the weight of each new rating difference in the
+	// exponential moving average of its pair's differences, or 0 if
+	// pairs hold plain averages.
+	emaAlpha float64
+
+	// frozenItems is true if ratings of items not already in the S1
+	// are skipped.
+	frozenItems bool
+
+	// trim is the fraction of the highest and of the lowest differences
+	// of each pair discarded when averaging them, or 0 if none are.
+	trim float64
+
+	// shrinkage is the weight, in co-ratings, of each item's mean
+	// rating when shrinking predictions towards it, or 0 if they are
+	// not shrunk.
+	shrinkage float64
+
+	// maxItemsPerUser is the number of ratings beyond which a user's
+	// rating differences are down-weighted, or 0 if they are not.
+	maxItemsPerUser int
+
+	// autoCompact is the number of users' ratings added or removed
+	// after which the S1 is compacted, or 0 if it is never compacted
+	// automatically.
+	autoCompact int
+}
+
+// defaultOptions returns the settings of a new S1.
+func defaultOptions() options {
+	return options{
+		floor: math.Inf(-1),
+		min:   math.Inf(-1),
+		max:   math.Inf(1),
+	}
+}
+
+// clone returns a copy of o that can be modified independently.
+func (o options) clone() options {
+	if o.scales != nil {
+		scales := make(map[int][2]float64, len(o.scales))
+		for i, sc := range o.scales {
+			scales[i] = sc
+		}
+		o.scales = scales
+	}
+	return o
+}
+
+// withOptions returns an empty *S1 with the same settings as s1.
+func (s1 *S1) withOptions() *S1 {
+	cp := NewS1()
+	cp.options = s1.options.clone()
+	if s1.w != nil {
+		cp.w = make(map[int]map[int]float64)
+	}
+	if s1.sq != nil {
+		cp.sq = make(map[int]map[int]float64)
+	}
+	if s1.comp != nil {
+		cp.comp = make(map[int]map[int]float64)
+	}
+	if s1.samples != nil {
+		cp.samples = make(map[int]map[int][]float64)
+	}
+	return cp
+}
+
+// SetSourceRatingFloor restricts predictions to be based only on the
+// items a user rated at least v, so that items the user disliked do
+// not influence what is recommended to them. Items rated below v are
+// still excluded from the user's predictions.
+//
+// The floor is compared against the user's ratings exactly as they are
+// provided, before any adjustment made during prediction. By default
+// there is no floor; setting v to math.Inf(-1) restores that.
+func (s1 *S1) SetSourceRatingFloor(v float64) {
+	if math.IsNaN(v) {
+		v = math.Inf(-1)
+	}
+	s1.floor = v
+	s1.gen++
+}
+
+// SetMinSupport restricts predictions to be based only on pairs of
+// items that have been rated together at least n times, ignoring
+// pairs with too little support to be trusted.
+func (s1 *S1) SetMinSupport(n int) {
+	s1.minSupport = n
+	s1.gen++
+}
+
+// PredictAtSupport is like Predict, but uses only pairs of items that
+// have been rated together at least minSupport times for this call,
+// in place of the minimum set with SetMinSupport, e.g., to compare
+// thresholds on a single model. It does not modify the S1, so it may
+// be called concurrently with other calls that read it.
+func (s1 *S1) PredictAtSupport(ur UserRatings, minSupport int) map[int]float64 {
+	cp := *s1
+	cp.minSupport = minSupport
+	return cp.predict(ur, false)
+}
+
+// SetMinContributors restricts predictions to items predicted from at
+// least n of the user's rated items, i.e., from n distinct items that
+// each have a pair with the predicted item usable in predictions. This
+// differs from SetMinSupport, which counts how often each pair of items
+// has been rated together: an item predicted from a single pair with
+// high support has one contributor.
+func (s1 *S1) SetMinContributors(n int) {
+	s1.minContributors = n
+	s1.gen++
+}
+
+// SetShrinkage shrinks predictions towards the predicted item's mean
+// rating, so that predictions of items with little evidence are
+// stabilised by the item's overall popularity. A prediction p based on
+// n co-ratings of the item with the user's rated items becomes
+//
+//	(n*p + k*mean) / (n + k)
+//
+// so the mean counts as k co-ratings: it dominates predictions based
+// on fewer than k co-ratings, and its influence fades as n grows. Items
+// without ratings of their own, and so without a mean, are not shrunk.
+// Predictions are shrunk before they are clamped to their rating scale
+// and rounded, and in log-odds mode the mean is converted to log-odds
+// first. A k of 0 or less, the default, disables shrinkage.
+func (s1 *S1) SetShrinkage(k float64) {
+	if !(k > 0) {
+		k = 0
+	}
+	s1.shrinkage = k
+	s1.gen++
+}
+
+// SetRatingScale clamps predictions to the rating scale [min, max],
+// for items without their own scale set with SetItemScale. By default
+// predictions are not clamped; setting min and max to math.Inf(-1) and
+// math.Inf(1) restores that.
+func (s1 *S1) SetRatingScale(min, max float64) {
+	s1.min, s1.max = min, max
+	s1.gen++
+}
+
+// SetItemScale clamps predictions of item to the rating scale
+// [min, max], e.g., when some items are rated on a scale of 1 to 5 and
+// others, on a scale of 0 to 1, are rated with a thumbs up or down.
+//
+// Scales only affect the clamping of predictions. The rating
+// differences between items are treated uniformly, regardless of the
+// items' scales.
+func (s1 *S1) SetItemScale(item int, min, max float64) {
+	if s1.scales == nil {
+		s1.scales = make(map[int][2]float64)
+	}
+	s1.scales[item] = [2]float64{min, max}
+	s1.gen++
+}
+
+// scale returns the bounds of the rating scale of item.
+func (o *options) scale(item int) (min, max float64) {
+	if sc, ok := o.scales[item]; ok {
+		return sc[0], sc[1]
+	}
+	return o.min, o.max
+}
+
+// SetRatingStep rounds predictions to the nearest multiple of step,
+// e.g., 0.5 for half-star ratings, after they have been clamped to
+// their rating scale. Predictions exactly between two multiples are
+// rounded away from zero. A step of 0, the default, means predictions
+// are not rounded.
+func (s1 *S1) SetRatingStep(step float64) {
+	if !(step > 0) {
+		step = 0
+	}
+	s1.step = step
+	s1.gen++
+}
+
+// SetPredictObserver sets a function to be called with every
+// prediction made by Predict, PredictAll and PredictSlices, e.g., to
+// log how a production recommendation was arrived at. fn is given the
+// predicted item, its predicted rating, and the number of co-ratings
+// the prediction is based on. Predictions that Predict returns from
+// the cache, if one is enabled with EnablePredictCache, were observed
+// when they were first made, and are not observed again.
+//
+// There is no observer by default; a nil fn removes the observer.
+func (s1 *S1) SetPredictObserver(fn func(item int, score float64, support int)) {
+	s1.observer = fn
+}
+
+// SetWeightFunc sets the function used to weight each pair of items'
+// contribution to a prediction, given the number of times the pair has
+// been rated together, e.g., to experiment with weighting schemes.
+//
+// By default, a contribution is weighted by the pair's support, as in
+// the Weighted Slope One scheme; a nil fn restores that. Pairs given a
+// weight of zero do not contribute to predictions, though they still
+// count towards their support.
+func (s1 *S1) SetWeightFunc(fn func(support int) float64) {
+	s1.weightFunc = fn
+	s1.gen++
+}
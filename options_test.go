@@ -0,0 +1,232 @@
+package slopeone
+
+import "testing"
+
+func TestSetSourceRatingFloor(t *testing.T) {
+	s1 := newFixture()
+	ur := UserRatings{2005: 2.0, 29074: 4.2}
+
+	// With a floor of 3, only the rating of 29074 is used.
+	want := s1.Predict(UserRatings{29074: 4.2})
+	delete(want, 2005)
+
+	s1.SetSourceRatingFloor(3)
+	if got := s1.Predict(ur); !samePredictions(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	ps := s1.NewSession()
+	ps.AddRating(2005, 2)
+	ps.AddRating(29074, 4.2)
+	for i, r := range want {
+		if got, ok := ps.Get(i); !ok || !near(got, r) {
+			t.Errorf("session item %d: got %v, %v, want %v", i, got, ok, r)
+		}
+	}
+	if _, ok := ps.Get(2005); ok {
+		t.Error("item rated below the floor was predicted")
+	}
+}
+
+func TestSetRatingStep(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{{1: 1, 2: 2.25, 3: 2.2, 4: 2.3, 5: 2.75}})
+	s1.SetRatingStep(0.5)
+
+	got := s1.Predict(UserRatings{1: 1})
+	want := map[int]float64{
+		2: 2.5, // exactly between 2 and 2.5
+		3: 2,
+		4: 2.5,
+		5: 3, // exactly between 2.5 and 3
+	}
+	for i, w := range want {
+		if got[i] != w {
+			t.Errorf("item %d: got %v, want %v", i, got[i], w)
+		}
+	}
+
+	s1.SetRatingStep(0)
+	if got := s1.Predict(UserRatings{1: 1})[3]; !near(got, 2.2) {
+		t.Errorf("unrounded: got %v, want 2.2", got)
+	}
+}
+
+func TestSetPredictObserver(t *testing.T) {
+	s1 := newFixture()
+	ur := UserRatings{2005: 2.0, 29074: 3.2}
+
+	observed := make(map[int]float64)
+	s1.SetPredictObserver(func(item int, score float64, support int) {
+		if support <= 0 {
+			t.Errorf("item %d: observed support %d", item, support)
+		}
+		if _, ok := observed[item]; ok {
+			t.Errorf("item %d observed twice", item)
+		}
+		observed[item] = score
+	})
+	if got := s1.Predict(ur); !samePredictions(got, observed) {
+		t.Errorf("got %v, observed %v", got, observed)
+	}
+
+	s1.SetPredictObserver(nil)
+	observed = make(map[int]float64)
+	s1.Predict(ur)
+	if len(observed) != 0 {
+		t.Errorf("removed observer observed %v", observed)
+	}
+}
+
+func TestSetPredictObserverCached(t *testing.T) {
+	s1 := newFixture()
+	s1.EnablePredictCache(1)
+	ur := UserRatings{2005: 2.0, 29074: 3.2}
+
+	observed := make(map[int]float64)
+	s1.SetPredictObserver(func(item int, score float64, support int) {
+		observed[item] = score
+	})
+	if got := s1.Predict(ur); !samePredictions(got, observed) {
+		t.Errorf("got %v, observed %v", got, observed)
+	}
+	observed = make(map[int]float64)
+	if s1.Predict(ur); len(observed) != 0 {
+		t.Errorf("cached predictions observed %v", observed)
+	}
+}
+
+func TestSetItemScale(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{{1: 1, 2: 5, 3: 1}})
+	s1.SetRatingScale(1, 5)
+	s1.SetItemScale(3, 0, 1)
+
+	tests := []struct {
+		ur   UserRatings
+		want map[int]float64
+	}{
+		{UserRatings{1: 4}, map[int]float64{2: 5, 3: 1}},
+		{UserRatings{1: 0.1}, map[int]float64{2: 4.1, 3: 0.1}},
+		{UserRatings{1: -2}, map[int]float64{2: 2, 3: 0}},
+	}
+	for _, tt := range tests {
+		if got := s1.Predict(tt.ur); !samePredictions(got, tt.want) {
+			t.Errorf("%v: got %v, want %v", tt.ur, got, tt.want)
+		}
+	}
+}
+
+func TestSetWeightFunc(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{{1: 1, 3: 2}, {1: 1, 3: 2}, {1: 1, 3: 2}, {2: 1, 3: 4}})
+	ur := UserRatings{1: 1, 2: 1}
+
+	// By support, item 1 predicts 2 with a weight of 3, and item 2
+	// predicts 4 with a weight of 1.
+	if got := s1.Predict(ur)[3]; !near(got, 2.5) {
+		t.Errorf("default: got %v, want 2.5", got)
+	}
+	s1.SetWeightFunc(func(int) float64 { return 1 })
+	if got := s1.Predict(ur)[3]; !near(got, 3) {
+		t.Errorf("unweighted: got %v, want 3", got)
+	}
+	s1.SetWeightFunc(func(support int) float64 { return float64(support * support) })
+	if got := s1.Predict(ur)[3]; !near(got, (9*2+4)/10.0) {
+		t.Errorf("squared: got %v, want %v", got, (9*2+4)/10.0)
+	}
+	s1.SetWeightFunc(nil)
+	if got := s1.Predict(ur)[3]; !near(got, 2.5) {
+		t.Errorf("restored: got %v, want 2.5", got)
+	}
+}
+
+func TestSetMinContributors(t *testing.T) {
+	// Item 9 has ten co-ratings with item 1 only; item 8 has a single
+	// co-rating with each of items 1, 2 and 3.
+	var users []UserRatings
+	for k := 0; k < 10; k++ {
+		users = append(users, UserRatings{1: 3, 9: 4})
+	}
+	users = append(users, UserRatings{1: 3, 2: 3, 3: 3, 8: 2})
+	s1 := NewS1()
+	s1.AddRatings(users)
+	s1.SetMinContributors(3)
+
+	got := s1.Predict(UserRatings{1: 3, 2: 3, 3: 3})
+	if _, ok := got[9]; ok {
+		t.Errorf("item 9 with one contributor was predicted: %v", got)
+	}
+	if _, ok := got[8]; !ok {
+		t.Errorf("item 8 with three contributors was not predicted: %v", got)
+	}
+
+	ps := s1.NewSession()
+	ps.AddRating(1, 3)
+	ps.AddRating(2, 3)
+	if _, ok := ps.Get(8); ok {
+		t.Error("session: item 8 with two contributors was predicted")
+	}
+	ps.AddRating(3, 3)
+	if _, ok := ps.Get(8); !ok {
+		t.Error("session: item 8 with three contributors was not predicted")
+	}
+}
+
+func TestPredictAtSupport(t *testing.T) {
+	s1 := newFixture()
+	s1.AddRatings(fixture[1:3])
+	ur := UserRatings{29074: 3, 2005: 2}
+	for n := 0; n <= 4; n++ {
+		want := newFixture()
+		want.AddRatings(fixture[1:3])
+		want.SetMinSupport(n)
+		if got, w := s1.PredictAtSupport(ur, n), want.Predict(ur); !samePredictions(got, w) {
+			t.Errorf("support %d: got %v, want %v", n, got, w)
+		}
+	}
+	if got, want := s1.PredictAtSupport(ur, 0), s1.Predict(ur); !samePredictions(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if len(s1.PredictAtSupport(ur, 4)) >= len(s1.PredictAtSupport(ur, 0)) {
+		t.Error("a higher support threshold did not narrow the predictions")
+	}
+}
+
+func TestSetShrinkage(t *testing.T) {
+	var data []UserRatings
+	for k := 0; k < 100; k++ {
+		data = append(data, UserRatings{1: 3, 2: 4})
+	}
+	data = append(data, UserRatings{1: 3, 3: 5})
+	for k := 0; k < 9; k++ {
+		data = append(data, UserRatings{3: 1})
+	}
+	s1 := NewS1()
+	s1.AddRatings(data)
+
+	ur := UserRatings{1: 4}
+	if got := s1.Predict(ur)[2]; !near(got, 5) {
+		t.Errorf("unshrunk: got %v, want 5", got)
+	}
+
+	// Item 2 is predicted 5 from 100 co-ratings and has a mean of 4.
+	// Item 3 is predicted 6 from a single co-rating, but has a mean of
+	// 1.4, so it is shrunk much further.
+	s1.SetShrinkage(5)
+	want := map[int]float64{2: (100*5 + 5*4) / 105.0, 3: (6 + 5*1.4) / 6}
+	if got := s1.Predict(ur); !samePredictions(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := s1.Freeze().Predict(ur); !samePredictions(got, want) {
+		t.Errorf("frozen: got %v, want %v", got, want)
+	}
+	ps := s1.NewSession()
+	ps.AddRating(1, 4)
+	if got, _ := ps.Get(3); !near(got, want[3]) {
+		t.Errorf("session: got %v, want %v", got, want[3])
+	}
+	if got, ok := s1.PredictItem(ur, 3); !ok || !near(got, want[3]) {
+		t.Errorf("PredictItem: got %v, want %v", got, want[3])
+	}
+}
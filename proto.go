@@ -0,0 +1,266 @@
+package slopeone
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"sort"
+)
+
+// The protobuf wire types used by the portable model format.
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+	protoFixed32 = 5
+)
+
+var errProtoUnsupported = errors.New("slopeone: models trained with these settings cannot be written in the portable format")
+
+// MarshalProto encodes the S1 in a portable protobuf format, e.g., for
+// serving the model from a service written in another language. The
+// format is described by the schema in slopeone.proto, distributed with
+// this package; any protobuf library can decode it with that schema.
+//
+// Only the model's items, pairs and rating counts are encoded, not its
+// settings. Models trained in log-odds mode, with variance weighting,
+// with trimming or with z-score normalization cannot be encoded.
+func (s1 *S1) MarshalProto() ([]byte, error) {
+	if s1.logOdds || s1.sq != nil || s1.samples != nil || s1.normalization == ZScore {
+		return nil, errProtoUnsupported
+	}
+
+	seen := make(map[int]bool, len(s1.f))
+	items := make([]int, 0, len(s1.f))
+	for i := range s1.f {
+		seen[i] = true
+		items = append(items, i)
+	}
+	for i := range s1.counts {
+		if !seen[i] {
+			items = append(items, i)
+		}
+	}
+	sort.Ints(items)
+
+	var out, item, pair []byte
+	for _, i := range items {
+		item = item[:0]
+		item = appendVarintField(item, 1, uint64(i))
+		item = appendVarintField(item, 2, uint64(s1.counts[i]))
+		item = appendFixed64Field(item, 3, math.Float64bits(s1.sums[i]))
+
+		others := make([]int, 0, len(s1.f[i]))
+		for j := range s1.f[i] {
+			others = append(others, j)
+		}
+		sort.Ints(others)
+		for _, j := range others {
+			pair = pair[:0]
+			pair = appendVarintField(pair, 1, uint64(j))
+			pair = appendFixed64Field(pair, 2, math.Float64bits(s1.d[i][j]))
+			pair = appendVarintField(pair, 3, uint64(s1.f[i][j]))
+			if s1.w != nil {
+				pair = appendFixed64Field(pair, 4, math.Float64bits(s1.w[i][j]))
+			}
+			item = appendBytesField(item, 4, pair)
+		}
+		out = appendBytesField(out, 1, item)
+	}
+	out = appendVarintField(out, 2, uint64(s1.ratings))
+	if s1.w != nil {
+		out = appendVarintField(out, 3, 1)
+	}
+	return out, nil
+}
+
+// UnmarshalProto replaces the S1's items, pairs and rating counts with
+// those encoded in data by MarshalProto. The S1's settings are kept,
+// but everything else that described the previous model is discarded:
+// users added with UpsertUser, ratings awaiting ExpireBefore, seeded
+// items and item metadata.
+//
+// If data is malformed the returned error is a *DecodeError of kind
+// ErrCorruptModel, and the S1 is unchanged.
+func (s1 *S1) UnmarshalProto(data []byte) error {
+	m := s1.withOptions()
+	var weighted bool
+	var items [][]byte
+	err := protoFields(data, func(field, wt int, v uint64, b []byte) error {
+		switch {
+		case field == 1 && wt == protoBytes:
+			items = append(items, b)
+		case field == 2 && wt == protoVarint:
+			m.ratings = int64(v)
+		case field == 3 && wt == protoVarint:
+			weighted = v != 0
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if weighted {
+		m.w = make(map[int]map[int]float64)
+	} else {
+		m.w = nil
+	}
+
+	seen := make(map[int]bool, len(items))
+	for _, b := range items {
+		if err := m.unmarshalProtoItem(b, seen); err != nil {
+			return err
+		}
+	}
+
+	// The pairs of items must be symmetric.
+	for i, freqs := range m.f {
+		for j, n := range freqs {
+			if m.f[j][i] != n {
+				return corrupt("pair %d, %d has frequency %d, but pair %d, %d has %d", i, j, n, j, i, m.f[j][i])
+			}
+		}
+	}
+
+	s1.d, s1.f, s1.w, s1.sq, s1.comp, s1.samples = m.d, m.f, m.w, m.sq, m.comp, m.samples
+	s1.counts, s1.sums, s1.ratings = m.counts, m.sums, m.ratings
+	s1.pairs, s1.fp = m.pairs, m.fp
+	s1.users = make(map[int]UserRatings)
+	s1.meta, s1.seeded, s1.observed, s1.updates = nil, nil, nil, 0
+	s1.gen++
+	return nil
+}
+
+// unmarshalProtoItem adds the item encoded in b to the S1, recording
+// it in seen, the items already decoded.
+func (s1 *S1) unmarshalProtoItem(b []byte, seen map[int]bool) error {
+	var id, count int
+	var sum float64
+	var pairs [][]byte
+	err := protoFields(b, func(field, wt int, v uint64, b []byte) error {
+		switch {
+		case field == 1 && wt == protoVarint:
+			id = int(int64(v))
+		case field == 2 && wt == protoVarint:
+			count = int(int64(v))
+		case field == 3 && wt == protoFixed64:
+			sum = math.Float64frombits(v)
+		case field == 4 && wt == protoBytes:
+			pairs = append(pairs, b)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if count < 0 {
+		return corrupt("item %d has %d ratings", id, count)
+	}
+	if seen[id] {
+		return corrupt("item %d is encoded more than once", id)
+	}
+	seen[id] = true
+	if count > 0 {
+		s1.counts[id], s1.sums[id] = count, sum
+	}
+
+	for _, b := range pairs {
+		var p pairStats
+		var other int
+		err := protoFields(b, func(field, wt int, v uint64, b []byte) error {
+			switch {
+			case field == 1 && wt == protoVarint:
+				other = int(int64(v))
+			case field == 2 && wt == protoFixed64:
+				p.sum = math.Float64frombits(v)
+			case field == 3 && wt == protoVarint:
+				p.n = int(int64(v))
+			case field == 4 && wt == protoFixed64:
+				p.w = math.Float64frombits(v)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if p.n <= 0 {
+			return corrupt("pair %d, %d has frequency %d", id, other, p.n)
+		}
+		if s1.w != nil && !(p.w > 0) {
+			return corrupt("pair %d, %d has weight %v", id, other, p.w)
+		}
+		if _, ok := s1.f[id][other]; ok {
+			return corrupt("pair %d, %d is encoded more than once", id, other)
+		}
+		s1.addRaw(id, other, p)
+	}
+	return nil
+}
+
+// appendVarintField appends a varint field to b.
+func appendVarintField(b []byte, field int, v uint64) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|protoVarint)
+	return binary.AppendUvarint(b, v)
+}
+
+// appendFixed64Field appends a fixed 64-bit field to b.
+func appendFixed64Field(b []byte, field int, v uint64) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|protoFixed64)
+	return binary.LittleEndian.AppendUint64(b, v)
+}
+
+// appendBytesField appends a length-delimited field to b.
+func appendBytesField(b []byte, field int, v []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|protoBytes)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+// protoFields calls fn with each field of the protobuf message in b.
+// For varint and fixed-size fields v holds the value; for
+// length-delimited fields data holds it. Fields of unknown wire types
+// are treated as corrupt.
+func protoFields(b []byte, fn func(field, wt int, v uint64, data []byte) error) error {
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return corrupt("truncated field key")
+		}
+		b = b[n:]
+		field, wt := int(key>>3), int(key&7)
+
+		var v uint64
+		var data []byte
+		switch wt {
+		case protoVarint:
+			if v, n = binary.Uvarint(b); n <= 0 {
+				return corrupt("truncated varint in field %d", field)
+			}
+			b = b[n:]
+		case protoFixed64:
+			if len(b) < 8 {
+				return corrupt("truncated fixed64 in field %d", field)
+			}
+			v, b = binary.LittleEndian.Uint64(b), b[8:]
+		case protoFixed32:
+			if len(b) < 4 {
+				return corrupt("truncated fixed32 in field %d", field)
+			}
+			v, b = uint64(binary.LittleEndian.Uint32(b)), b[4:]
+		case protoBytes:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || l > uint64(len(b)-n) {
+				return corrupt("truncated length-delimited field %d", field)
+			}
+			data, b = b[n:n+int(l)], b[n+int(l):]
+		default:
+			return corrupt("field %d has unsupported wire type %d", field, wt)
+		}
+
+		if err := fn(field, wt, v, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package slopeone
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestProtoRoundTrip(t *testing.T) {
+	weighted := NewS1()
+	weighted.AddWeightedRatings([]map[int]WeightedRating{{1: {Value: 3, Weight: 0.5}, 2: {Value: 4, Weight: 1}}})
+	plain := NewS1()
+	plain.AddRatings(GenerateRatings(30, 15, 0.4, 2))
+	plain.AddRatings([]UserRatings{{-5: 2, 3: 1}})
+
+	for name, s1 := range map[string]*S1{"plain": plain, "weighted": weighted} {
+		b, err := s1.MarshalProto()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		got := NewS1()
+		if err := got.UnmarshalProto(b); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got.Fingerprint() != s1.Fingerprint() || got.NumRatings() != s1.NumRatings() || got.NumPairs() != s1.NumPairs() {
+			t.Errorf("%s: decoded model differs", name)
+		}
+		for i := range s1.counts {
+			want, _ := s1.ItemMean(i)
+			if m, ok := got.ItemMean(i); !ok || !near(m, want) {
+				t.Errorf("%s: item %d: got mean %v, %v, want %v", name, i, m, ok, want)
+			}
+		}
+
+		// Truncating the data partway through an item corrupts it, and a
+		// failed decode leaves the model unchanged.
+		if err := got.UnmarshalProto(b[:len(b)/2]); !errors.Is(err, ErrCorruptModel) {
+			t.Errorf("%s: truncated: got %v, want %v", name, err, ErrCorruptModel)
+		}
+		if got.Fingerprint() != s1.Fingerprint() {
+			t.Errorf("%s: failed decode changed the model", name)
+		}
+	}
+}
+
+func TestUnmarshalProtoReplaces(t *testing.T) {
+	src := newFixture()
+	b, err := src.MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t0 := time.Unix(1000, 0)
+	s1 := NewS1()
+	s1.AddRatingsAt([]UserRatings{{1: 3, 2: 4}}, t0)
+	s1.AddRatings([]UserRatings{{7: 1, 8: 2}})
+	s1.SeedItemFromNeighbors(99, []int{7})
+	s1.SetItemMeta(2005, "old")
+	if err := s1.UnmarshalProto(b); err != nil {
+		t.Fatal(err)
+	}
+
+	// Expiring, or rating the previously seeded item, must not remove
+	// anything from the decoded model.
+	s1.ExpireBefore(t0.Add(time.Hour))
+	s1.AddRatings([]UserRatings{{99: 1}})
+	s1.RemoveRatings([]UserRatings{{99: 1}})
+	if s1.Fingerprint() != src.Fingerprint() || s1.NumRatings() != src.NumRatings() {
+		t.Errorf("got %d ratings, want the decoded model's %d", s1.NumRatings(), src.NumRatings())
+	}
+	if got := s1.TopNWithMeta(UserRatings{1: 3}, 10); len(got) == 0 || got[0].Meta != nil {
+		t.Errorf("got %v, want recommendations without metadata", got)
+	}
+}
+
+func TestUnmarshalProtoDuplicateItem(t *testing.T) {
+	// Item 5 is encoded twice, without ratings either time.
+	item := appendVarintField(nil, 1, 5)
+	data := appendBytesField(appendBytesField(nil, 1, item), 1, item)
+	if err := NewS1().UnmarshalProto(data); !errors.Is(err, ErrCorruptModel) {
+		t.Errorf("got %v, want %v", err, ErrCorruptModel)
+	}
+}
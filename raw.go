@@ -0,0 +1,24 @@
+package slopeone
+
+// PredictRaw returns, for each item Predict would predict for the
+// provided user, the two halves of the weighted average the prediction
+// is made from: num, the weighted sum of the predictions made from each
+// of the user's rated items, and den, the total of their weights.
+//
+// num[i] / den[i] equals the prediction Predict returns for item i,
+// before it is shrunk towards the item's mean, mapped out of log-odds
+// space, clamped to the item's rating scale and rounded, so with the
+// default settings the two are the same. Keeping the halves separate
+// lets callers combine them across models, e.g., summing the
+// numerators and denominators of models trained on disjoint users,
+// before dividing.
+func (s1 *S1) PredictRaw(ur UserRatings) (num, den map[int]float64) {
+	est := s1.estimates(ur, false)
+	num, den = make(map[int]float64, len(est)), make(map[int]float64, len(est))
+	for i, e := range est {
+		if _, ok := s1.finish(i, e); ok {
+			num[i], den[i] = e.sum, e.weight
+		}
+	}
+	return num, den
+}
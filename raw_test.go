@@ -0,0 +1,19 @@
+package slopeone
+
+import "testing"
+
+func TestPredictRaw(t *testing.T) {
+	s1 := newFixture()
+	for _, ur := range fixture {
+		want := s1.Predict(ur)
+		num, den := s1.PredictRaw(ur)
+		if len(num) != len(want) || len(den) != len(want) {
+			t.Fatalf("%v: got %v / %v, want %v", ur, num, den, want)
+		}
+		for i, r := range want {
+			if got := num[i] / den[i]; !near(got, r) {
+				t.Errorf("%v, item %d: got %v, want %v", ur, i, got, r)
+			}
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package slopeone
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+)
+
+// Recommendation is a predicted rating of an item for a user.
+type Recommendation struct {
+	Item   int
+	Rating float64
+
+	// Support is the number of co-ratings between the item and the
+	// user's rated items that the prediction is based on.
+	Support int
+}
+
+// ranksBefore reports whether a should be recommended ahead of b: it
+// has a higher rating or, for equal ratings, a lower item.
+func ranksBefore(a, b Recommendation) bool {
+	if a.Rating != b.Rating {
+		return a.Rating > b.Rating
+	}
+	return a.Item < b.Item
+}
+
+// sortRecommendations sorts recs by descending rating, breaking ties
+// by ascending item.
+func sortRecommendations(recs []Recommendation) {
+	sort.Slice(recs, func(a, b int) bool {
+		return ranksBefore(recs[a], recs[b])
+	})
+}
+
+// recHeap is a heap of recommendations, with the lowest ranked, by
+// before, at the root, used to keep the best n of many
+// recommendations.
+type recHeap struct {
+	recs   []Recommendation
+	before func(a, b Recommendation) bool
+}
+
+func (h *recHeap) Len() int           { return len(h.recs) }
+func (h *recHeap) Less(a, b int) bool { return h.before(h.recs[b], h.recs[a]) }
+func (h *recHeap) Swap(a, b int)      { h.recs[a], h.recs[b] = h.recs[b], h.recs[a] }
+func (h *recHeap) Push(x any)         { h.recs = append(h.recs, x.(Recommendation)) }
+func (h *recHeap) Pop() any {
+	x := h.recs[len(h.recs)-1]
+	h.recs = h.recs[:len(h.recs)-1]
+	return x
+}
+
+// offer adds rec to h if h holds fewer than n recommendations, or if
+// rec ranks ahead of the lowest ranked recommendation in h, which it
+// replaces.
+func (h *recHeap) offer(rec Recommendation, n int) {
+	if len(h.recs) < n {
+		heap.Push(h, rec)
+	} else if h.before(rec, h.recs[0]) {
+		h.recs[0] = rec
+		heap.Fix(h, 0)
+	}
+}
+
+// TopN returns the provided user's n highest predicted ratings, sorted
+// by descending rating.
+//
+// Rather than predicting the ratings of every item and then sorting
+// them, each candidate item is predicted in turn and only the best n
+// are kept, so TopN needs memory proportional to n regardless of the
+// number of items.
+func (s1 *S1) TopN(ur UserRatings, n int) []Recommendation {
+	return s1.TopNWith(ur, n, ranksBefore)
+}
+
+// TopNWith is like TopN, but ranks recommendations by less rather than
+// by descending rating: less reports whether a should be recommended
+// ahead of b, e.g., to rank by rating times the log of the item's
+// popularity, or to break ties differently. less must define a strict
+// weak ordering, and ties it leaves are broken arbitrarily.
+//
+// The best n recommendations by less are returned, sorted by less.
+func (s1 *S1) TopNWith(ur UserRatings, n int, less func(a, b Recommendation) bool) []Recommendation {
+	if n <= 0 {
+		return nil
+	}
+
+	// No more than every item can be recommended, however large n is.
+	size := n
+	if size > len(s1.d) {
+		size = len(s1.d)
+	}
+	h := &recHeap{recs: make([]Recommendation, 0, size), before: less}
+	sd := s1.spread(ur)
+	for gi := range s1.d {
+		if _, ok := ur[gi]; ok {
+			continue
+		}
+
+		var e estimate
+		for i, r := range ur {
+			s1.contribute(&e, gi, i, r, sd)
+		}
+		if r, ok := s1.finish(gi, e); ok {
+			h.offer(Recommendation{Item: gi, Rating: r, Support: e.support}, n)
+		}
+	}
+
+	recs := h.recs
+	sort.Slice(recs, func(a, b int) bool {
+		return less(recs[a], recs[b])
+	})
+	return recs
+}
+
+// PredictSorted returns every prediction Predict would return for the
+// provided user, as recommendations sorted by descending rating with
+// ties broken by ascending item, as in TopN, e.g., to be streamed in
+// order to a client.
+func (s1 *S1) PredictSorted(ur UserRatings) []Recommendation {
+	est := s1.estimates(ur, false)
+	recs := make([]Recommendation, 0, len(est))
+	for i, e := range est {
+		if r, ok := s1.finish(i, e); ok {
+			recs = append(recs, Recommendation{Item: i, Rating: r, Support: e.support})
+		}
+	}
+	sortRecommendations(recs)
+	return recs
+}
+
+// PredictTiered returns the provided user's predicted ratings, bucketed
+// into tiers by their support, e.g., for "strong picks", "you might
+// like" and "exploratory" recommendations.
+//
+// Each of tiers is a minimum support, and a prediction belongs to the
+// tier with the highest minimum that its support meets. The result maps
+// each tier's minimum support to its predictions, sorted by descending
+// rating. Predictions with less support than every tier are omitted.
+func (s1 *S1) PredictTiered(ur UserRatings, tiers []int) map[int][]Recommendation {
+	mins := append([]int(nil), tiers...)
+	sort.Sort(sort.Reverse(sort.IntSlice(mins)))
+
+	out := make(map[int][]Recommendation, len(mins))
+	for i, e := range s1.estimates(ur, false) {
+		r, ok := s1.finish(i, e)
+		if !ok {
+			continue
+		}
+		for _, min := range mins {
+			if e.support >= min {
+				out[min] = append(out[min], Recommendation{Item: i, Rating: r, Support: e.support})
+				break
+			}
+		}
+	}
+
+	for _, recs := range out {
+		sortRecommendations(recs)
+	}
+	return out
+}
+
+// TopNForAll returns the n highest predicted ratings, as returned by
+// TopN, for each of the users in profiles, which maps user ids to their
+// ratings. Users are processed in parallel, so the S1 must not be
+// modified until TopNForAll returns.
+func (s1 *S1) TopNForAll(profiles map[int]UserRatings, n int) map[int][]Recommendation {
+	users := make([]int, 0, len(profiles))
+	for u := range profiles {
+		users = append(users, u)
+	}
+
+	out := make(map[int][]Recommendation, len(profiles))
+	var mu sync.Mutex
+	parallel(users, func(u int) {
+		recs := s1.TopN(profiles[u], n)
+		mu.Lock()
+		out[u] = recs
+		mu.Unlock()
+	})
+	return out
+}
+
+// MergeTopN merges recommendations made for the same user by several
+// models, e.g., sub-models covering different parts of a catalog, into
+// their n highest ranked. Each of results must be sorted as returned by
+// TopN. An item recommended by more than one model is included once,
+// with its highest ranked recommendation.
+//
+// The lists are merged lazily, so only as many recommendations are
+// visited as are needed to find the best n.
+func MergeTopN(results [][]Recommendation, n int) []Recommendation {
+	h := make(mergeHeap, 0, len(results))
+	for _, recs := range results {
+		if len(recs) > 0 {
+			h = append(h, recs)
+		}
+	}
+	heap.Init(&h)
+
+	var out []Recommendation
+	seen := make(map[int]bool)
+	for len(out) < n && len(h) > 0 {
+		rec := h[0][0]
+		if h[0] = h[0][1:]; len(h[0]) == 0 {
+			heap.Pop(&h)
+		} else {
+			heap.Fix(&h, 0)
+		}
+
+		if !seen[rec.Item] {
+			seen[rec.Item] = true
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+// mergeHeap is a heap of sorted, non-empty lists of recommendations,
+// with the list whose first recommendation ranks highest at the root.
+type mergeHeap [][]Recommendation
+
+func (h mergeHeap) Len() int           { return len(h) }
+func (h mergeHeap) Less(a, b int) bool { return ranksBefore(h[a][0], h[b][0]) }
+func (h mergeHeap) Swap(a, b int)      { h[a], h[b] = h[b], h[a] }
+func (h *mergeHeap) Push(x any)        { *h = append(*h, x.([]Recommendation)) }
+func (h *mergeHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
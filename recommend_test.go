@@ -0,0 +1,192 @@
+package slopeone
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestPredictTiered(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{
+		{1: 1, 2: 2, 3: 3, 4: 4},
+		{1: 1, 2: 3, 3: 1},
+		{1: 1, 2: 5},
+	})
+
+	// Item 2 has a support of 3, item 3 of 2, and item 4 of 1.
+	got := s1.PredictTiered(UserRatings{1: 1}, []int{1, 3})
+	want := map[int][]int{3: {2}, 1: {4, 3}}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want tiers %v", got, want)
+	}
+	for tier, items := range want {
+		recs := got[tier]
+		if len(recs) != len(items) {
+			t.Errorf("tier %d: got %v, want items %v", tier, recs, items)
+			continue
+		}
+		for k, i := range items {
+			if recs[k].Item != i || recs[k].Support < tier {
+				t.Errorf("tier %d: got %v, want items %v", tier, recs, items)
+				break
+			}
+		}
+	}
+}
+
+// naiveTopN returns the n highest predicted ratings for ur by
+// predicting and sorting every item.
+func naiveTopN(s1 *S1, ur UserRatings, n int) []Recommendation {
+	var recs []Recommendation
+	for i, r := range s1.Predict(ur) {
+		recs = append(recs, Recommendation{Item: i, Rating: r})
+	}
+	sortRecommendations(recs)
+	if n < len(recs) {
+		recs = recs[:n]
+	}
+	return recs
+}
+
+func TestTopN(t *testing.T) {
+	data := GenerateRatings(200, 300, 0.05, 3)
+	s1 := NewS1()
+	s1.AddRatings(data)
+
+	for _, n := range []int{1, 5, 50, 1000, math.MaxInt} {
+		for _, ur := range data[:5] {
+			got, want := s1.TopN(ur, n), naiveTopN(s1, ur, n)
+			if len(got) != len(want) {
+				t.Fatalf("n %d: got %d recommendations, want %d", n, len(got), len(want))
+			}
+			for k := range want {
+				if got[k].Item != want[k].Item || !near(got[k].Rating, want[k].Rating) {
+					t.Errorf("n %d: got %v at %d, want %v", n, got[k], k, want[k])
+				}
+			}
+		}
+	}
+	if got := s1.TopN(data[0], 0); len(got) != 0 {
+		t.Errorf("n 0: got %v", got)
+	}
+}
+
+// BenchmarkTopN and BenchmarkTopNNaive report the memory allocated to
+// recommend the best n of a large number of items: TopN's stays
+// proportional to n, while the naive approach's grows with the number
+// of items.
+func BenchmarkTopN(b *testing.B) {
+	s1, ur := newTopNBenchmark()
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for k := 0; k < b.N; k++ {
+				s1.TopN(ur, n)
+			}
+		})
+	}
+}
+
+func BenchmarkTopNNaive(b *testing.B) {
+	s1, ur := newTopNBenchmark()
+	b.ResetTimer()
+	b.ReportAllocs()
+	for k := 0; k < b.N; k++ {
+		naiveTopN(s1, ur, 10)
+	}
+}
+
+// newTopNBenchmark returns a model of many items, and a user for whom
+// most of them can be predicted.
+func newTopNBenchmark() (*S1, UserRatings) {
+	const items = 20000
+	s1 := NewS1()
+	s1.AddRatings(GenerateRatings(4000, items, 0.001, 1))
+	ur := make(UserRatings)
+	for i := 0; i < items; i += 500 {
+		ur[i] = 3
+	}
+	return s1, ur
+}
+
+func TestTopNForAll(t *testing.T) {
+	data := GenerateRatings(100, 50, 0.1, 9)
+	s1 := NewS1()
+	s1.AddRatings(data)
+
+	profiles := make(map[int]UserRatings)
+	for u := 0; u < 20; u++ {
+		profiles[u] = data[u]
+	}
+	got := s1.TopNForAll(profiles, 5)
+	if len(got) != len(profiles) {
+		t.Fatalf("got %d users, want %d", len(got), len(profiles))
+	}
+	for u, ur := range profiles {
+		if want := s1.TopN(ur, 5); !reflect.DeepEqual(got[u], want) {
+			t.Errorf("user %d: got %v, want %v", u, got[u], want)
+		}
+	}
+}
+
+func TestMergeTopN(t *testing.T) {
+	rec := func(i int, r float64) Recommendation {
+		return Recommendation{Item: i, Rating: r}
+	}
+	// Item 1 is recommended by both shards: the higher rating is kept.
+	got := MergeTopN([][]Recommendation{
+		{rec(1, 5), rec(2, 3), rec(3, 1)},
+		{},
+		{rec(2, 4), rec(4, 3), rec(1, 2)},
+	}, 3)
+	want := []Recommendation{rec(1, 5), rec(2, 4), rec(4, 3)}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := MergeTopN(nil, 3); len(got) != 0 {
+		t.Errorf("no shards: got %v", got)
+	}
+}
+
+func TestTopNWith(t *testing.T) {
+	s1 := newFixture()
+	ur := UserRatings{2005: 3}
+	asc := func(a, b Recommendation) bool {
+		if a.Rating != b.Rating {
+			return a.Rating < b.Rating
+		}
+		return a.Item < b.Item
+	}
+
+	// The lowest rated items come first, so the ranking is TopN's
+	// reversed.
+	got := s1.TopNWith(ur, 3, asc)
+	all := s1.TopN(ur, len(s1.d))
+	if len(got) != 3 || len(all) < 3 {
+		t.Fatalf("got %v from %v, want 3 recommendations", got, all)
+	}
+	for k := range got {
+		if want := all[len(all)-1-k]; got[k].Rating != want.Rating {
+			t.Errorf("position %d: got %v, want %v", k, got[k], want)
+		}
+	}
+}
+
+func TestPredictSorted(t *testing.T) {
+	s1 := newFixture()
+	ur := UserRatings{2005: 3}
+	got := s1.PredictSorted(ur)
+	p := s1.Predict(ur)
+	want := s1.TopN(ur, len(p))
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for k, r := range got {
+		if r != want[k] || !near(p[r.Item], r.Rating) {
+			t.Errorf("got %v, want %v", got, want)
+			break
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package slopeone
+
+import (
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// ItemScore is an item along with a score, the meaning of which depends
+// on how it was produced.
+type ItemScore struct {
+	Item  int
+	Score float64
+}
+
+// sortItemScores sorts scores by descending score, breaking ties by
+// ascending item.
+func sortItemScores(scores []ItemScore) {
+	sort.Slice(scores, func(a, b int) bool {
+		if scores[a].Score != scores[b].Score {
+			return scores[a].Score > scores[b].Score
+		}
+		return scores[a].Item < scores[b].Item
+	})
+}
+
+// similarity returns how related items i1 and i2 are, as described by
+// MostSimilar.
+func (s1 *S1) similarity(i1, i2 int) float64 {
+	dev, ok := s1.deviation(i1, i2)
+	if !ok {
+		return 0
+	}
+	n := float64(s1.f[i1][i2])
+	return n / (n + 1) / (1 + math.Abs(dev))
+}
+
+// MostSimilar returns the k items most related to item, e.g., for
+// "because you liked" recommendations, sorted by descending
+// similarity.
+//
+// Items are more related the closer their ratings are and the more
+// often they have been rated together: a pair rated together n times,
+// with an average rating difference of dev, has a similarity of
+//
+//	n / (n + 1) / (1 + |dev|)
+//
+// Similarities are in [0, 1), and items that have never been rated
+// together have a similarity of 0. The package's other measures of how
+// related items are, such as DiversifyTopN's, use the same similarity.
+func (s1 *S1) MostSimilar(item, k int) []ItemScore {
+	if k <= 0 {
+		return nil
+	}
+
+	scores := make([]ItemScore, 0, len(s1.f[item]))
+	for j := range s1.f[item] {
+		if j != item {
+			scores = append(scores, ItemScore{Item: j, Score: s1.similarity(item, j)})
+		}
+	}
+	sortItemScores(scores)
+	if len(scores) > k {
+		scores = scores[:k:k]
+	}
+	return scores
+}
+
+// BuildRelated precomputes the k most related items, as returned by
+// MostSimilar, for every item in the S1, so that serving them is a
+// map lookup. Items are processed in parallel, so the S1 must not be
+// modified until BuildRelated returns.
+func (s1 *S1) BuildRelated(k int) map[int][]ItemScore {
+	items := make([]int, 0, len(s1.f))
+	for i := range s1.f {
+		items = append(items, i)
+	}
+
+	out := make(map[int][]ItemScore, len(items))
+	var mu sync.Mutex
+	parallel(items, func(i int) {
+		related := s1.MostSimilar(i, k)
+		mu.Lock()
+		out[i] = related
+		mu.Unlock()
+	})
+	return out
+}
+
+// MostConnected returns the k items with the highest total support,
+// the number of times they have been rated together with any other
+// item, sorted by descending support. These are the items that
+// participate in the most co-ratings, and so shape the most
+// predictions.
+func (s1 *S1) MostConnected(k int) []ItemScore {
+	if k <= 0 {
+		return nil
+	}
+
+	scores := make([]ItemScore, 0, len(s1.f))
+	for i, freqs := range s1.f {
+		var n int
+		for j, f := range freqs {
+			if j != i {
+				n += f
+			}
+		}
+		if n > 0 {
+			scores = append(scores, ItemScore{Item: i, Score: float64(n)})
+		}
+	}
+	sortItemScores(scores)
+	if len(scores) > k {
+		scores = scores[:k:k]
+	}
+	return scores
+}
+
+// parallel calls fn with each of keys, from as many goroutines as can
+// run simultaneously, returning once every call has returned.
+func parallel(keys []int, fn func(k int)) {
+	ch := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.GOMAXPROCS(0); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range ch {
+				fn(k)
+			}
+		}()
+	}
+
+	for _, k := range keys {
+		ch <- k
+	}
+	close(ch)
+	wg.Wait()
+}
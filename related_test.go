@@ -0,0 +1,32 @@
+package slopeone
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildRelated(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings(GenerateRatings(100, 50, 0.2, 9))
+
+	related := s1.BuildRelated(5)
+	if len(related) != 50 {
+		t.Fatalf("got %d items, want 50", len(related))
+	}
+	for i := 0; i < 50; i += 7 {
+		want := s1.MostSimilar(i, 5)
+		if len(want) != 5 || !reflect.DeepEqual(related[i], want) {
+			t.Errorf("item %d: got %v, want %v", i, related[i], want)
+		}
+	}
+}
+
+func TestMostConnected(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{{1: 1, 2: 2}, {1: 1, 3: 2}, {1: 3, 4: 1}, {5: 1}})
+
+	got := s1.MostConnected(2)
+	if len(got) != 2 || got[0].Item != 1 || got[0].Score != 3 || got[1].Item != 2 || got[1].Score != 1 {
+		t.Errorf("got %v, want hub item 1 and then item 2", got)
+	}
+}
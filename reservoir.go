@@ -0,0 +1,116 @@
+package slopeone
+
+import (
+	"math/rand"
+	"time"
+)
+
+// ReservoirS1 is an S1 trained on a bounded, uniform random sample of
+// an unbounded stream of rating events, using reservoir sampling. Its
+// memory use is bounded by its capacity, while its predictions
+// approximate those of a model trained on the whole stream.
+//
+// Ratings should only be added to a ReservoirS1 with Add, as the
+// sample is maintained using the embedded S1's UpsertUser.
+type ReservoirS1 struct {
+	*S1
+
+	capacity int
+	rnd      *rand.Rand
+
+	// seen is the number of distinct events offered to the reservoir.
+	seen int64
+
+	// sample holds the sampled events, and slots maps each sampled
+	// user and item to its index in sample.
+	sample []RatingEvent
+	slots  map[userItem]int
+
+	// profiles holds each user's sampled ratings.
+	profiles map[int]UserRatings
+}
+
+// userItem identifies a user's rating of an item.
+type userItem struct{ user, item int }
+
+// NewReservoirS1 returns a *ReservoirS1, ready for use, that samples
+// at most capacity rating events.
+func NewReservoirS1(capacity int) *ReservoirS1 {
+	return NewReservoirS1WithRand(capacity, rand.New(rand.NewSource(time.Now().UnixNano())))
+}
+
+// NewReservoirS1WithRand is like NewReservoirS1, but the returned
+// ReservoirS1 chooses which events to sample using rnd, e.g., a rnd
+// with a fixed seed so that the sample is reproducible. rnd is used by
+// every call to Add.
+func NewReservoirS1WithRand(capacity int, rnd *rand.Rand) *ReservoirS1 {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &ReservoirS1{
+		S1:       NewS1(),
+		capacity: capacity,
+		rnd:      rnd,
+		slots:    make(map[userItem]int),
+		profiles: make(map[int]UserRatings),
+	}
+}
+
+// Len returns the number of events currently sampled.
+func (rs *ReservoirS1) Len() int {
+	return len(rs.sample)
+}
+
+// Add offers a rating event to the ReservoirS1.
+//
+// Until the reservoir is full every event is sampled. After that, the
+// n-th distinct event is sampled with probability capacity/n, in which
+// case a random sampled event is evicted and its contribution removed
+// from the model. An event for a user and item that is already sampled
+// replaces the sampled rating instead.
+func (rs *ReservoirS1) Add(ev RatingEvent) {
+	key := userItem{ev.User, ev.Item}
+	if k, ok := rs.slots[key]; ok {
+		rs.sample[k] = ev
+		rs.profiles[ev.User][ev.Item] = ev.Rating
+		rs.refresh(ev.User)
+		return
+	}
+
+	rs.seen++
+	k := len(rs.sample)
+	if k < rs.capacity {
+		rs.sample = append(rs.sample, ev)
+	} else {
+		if k = int(rs.rnd.Int63n(rs.seen)); k >= rs.capacity {
+			return
+		}
+
+		old := rs.sample[k]
+		delete(rs.slots, userItem{old.User, old.Item})
+		delete(rs.profiles[old.User], old.Item)
+		rs.sample[k] = ev
+		if old.User != ev.User {
+			rs.refresh(old.User)
+		}
+	}
+
+	rs.slots[key] = k
+	if _, ok := rs.profiles[ev.User]; !ok {
+		rs.profiles[ev.User] = make(UserRatings)
+	}
+	rs.profiles[ev.User][ev.Item] = ev.Rating
+	rs.refresh(ev.User)
+}
+
+// refresh replaces the user's ratings in the model with their sampled
+// ratings.
+func (rs *ReservoirS1) refresh(user int) {
+	if p := rs.profiles[user]; len(p) > 0 {
+		rs.S1.UpsertUser(user, p)
+		return
+	}
+	rs.S1.UpsertUser(user, nil)
+	delete(rs.S1.users, user)
+	delete(rs.profiles, user)
+}
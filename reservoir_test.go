@@ -0,0 +1,57 @@
+package slopeone
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestReservoirS1(t *testing.T) {
+	const capacity = 500
+	data := GenerateRatings(300, 50, 0.2, 5)
+	full := NewS1()
+	full.AddRatings(data)
+
+	rs := NewReservoirS1(capacity)
+	for u, ur := range data {
+		for i, r := range ur {
+			rs.Add(RatingEvent{User: u, Item: i, Rating: r})
+		}
+	}
+	if rs.Len() != capacity || rs.NumRatings() != capacity {
+		t.Fatalf("got %d events and %d ratings, want %d", rs.Len(), rs.NumRatings(), capacity)
+	}
+
+	// The sample's predictions should be close to the full model's.
+	var sq float64
+	var n int
+	got, want := rs.Predict(data[0]), full.Predict(data[0])
+	for i, r := range got {
+		if w, ok := want[i]; ok {
+			sq += (r - w) * (r - w)
+			n++
+		}
+	}
+	if n == 0 || sq/float64(n) > 1.5 {
+		t.Errorf("got mean squared difference %v over %d items", sq/float64(n), n)
+	}
+}
+
+func TestNewReservoirS1WithRand(t *testing.T) {
+	data := GenerateRatings(20, 10, 0.5, 3)
+	sample := func() uint64 {
+		rs := NewReservoirS1WithRand(10, rand.New(rand.NewSource(9)))
+		// The events are added in the same order each time, unlike the
+		// order of ranging over the users' ratings.
+		for u, ur := range data {
+			for i := 0; i < 10; i++ {
+				if r, ok := ur[i]; ok {
+					rs.Add(RatingEvent{User: u, Item: i, Rating: r})
+				}
+			}
+		}
+		return rs.Fingerprint()
+	}
+	if a, b := sample(), sample(); a != b {
+		t.Errorf("got fingerprints %x and %x from the same source", a, b)
+	}
+}
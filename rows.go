@@ -0,0 +1,58 @@
+package slopeone
+
+import "fmt"
+
+// RowScanner is a source of rows of ratings, satisfied by *sql.Rows.
+type RowScanner interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+}
+
+// IngestRows adds the ratings in rows to the S1, e.g., to train
+// directly from the result of a database query such as
+//
+//	SELECT user_id, item_id, rating FROM ratings ORDER BY user_id
+//
+// Each row must have three columns, the user, the item and the rating,
+// which are scanned into an int, an int and a float64.
+//
+// Rows must be ordered, or at least grouped, by user: the ratings of
+// each run of consecutive rows with the same user are added as a single
+// user, as if by AddRatings, when the next user's rows begin, so only
+// one user's ratings are held in memory at a time. A user whose rows
+// are not consecutive is added as several users. If a user rated an
+// item more than once the last rating is used.
+//
+// If an error occurs, the users whose rows were all read before it
+// remain added.
+func (s1 *S1) IngestRows(rows RowScanner) error {
+	var (
+		current int
+		user    UserRatings
+	)
+	flush := func() {
+		if len(user) > 0 {
+			s1.AddRatings([]UserRatings{user})
+		}
+	}
+
+	for n := 1; rows.Next(); n++ {
+		var id, item int
+		var rating float64
+		if err := rows.Scan(&id, &item, &rating); err != nil {
+			return fmt.Errorf("slopeone: row %d: %w", n, err)
+		}
+
+		if user == nil || id != current {
+			flush()
+			current, user = id, make(UserRatings)
+		}
+		user[item] = rating
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	flush()
+	return nil
+}
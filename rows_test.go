@@ -0,0 +1,64 @@
+package slopeone
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeRows is a RowScanner over rows of user, item and rating. A row
+// with a negative user fails to scan.
+type fakeRows struct {
+	rows [][3]float64
+	k    int
+	err  error
+}
+
+func (r *fakeRows) Next() bool {
+	r.k++
+	return r.k <= len(r.rows)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	row := r.rows[r.k-1]
+	if row[0] < 0 {
+		return errors.New("bad row")
+	}
+	*dest[0].(*int), *dest[1].(*int), *dest[2].(*float64) = int(row[0]), int(row[1]), row[2]
+	return nil
+}
+
+func (r *fakeRows) Err() error {
+	return r.err
+}
+
+func TestIngestRows(t *testing.T) {
+	s1 := NewS1()
+	rows := &fakeRows{rows: [][3]float64{{1, 1, 5}, {1, 2, 3}, {2, 1, 4}, {2, 2, 4}, {2, 2, 2}}}
+	if err := s1.IngestRows(rows); err != nil {
+		t.Fatal(err)
+	}
+
+	// User 2's second rating of item 2 replaces the first.
+	want := NewS1()
+	want.AddRatings([]UserRatings{{1: 5, 2: 3}, {1: 4, 2: 2}})
+	if s1.Fingerprint() != want.Fingerprint() || s1.NumRatings() != 4 {
+		t.Errorf("got %d ratings, fingerprint %x, want 4 and %x", s1.NumRatings(), s1.Fingerprint(), want.Fingerprint())
+	}
+}
+
+func TestIngestRowsErrors(t *testing.T) {
+	bad := &fakeRows{rows: [][3]float64{{1, 1, 5}, {1, 2, 3}, {2, 1, 4}, {-1, 0, 0}}}
+	s1 := NewS1()
+	if err := s1.IngestRows(bad); err == nil {
+		t.Error("got nil error for a row that fails to scan")
+	}
+	// User 1's rows were all read before the error.
+	if got := s1.NumRatings(); got != 2 {
+		t.Errorf("got %d ratings, want user 1's 2", got)
+	}
+
+	failed := errors.New("connection lost")
+	if err := NewS1().IngestRows(&fakeRows{err: failed}); !errors.Is(err, failed) {
+		t.Errorf("got %v, want %v", err, failed)
+	}
+}
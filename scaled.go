@@ -0,0 +1,23 @@
+package slopeone
+
+import "math"
+
+// PredictScaled is like Predict, but returns predictions on the rating
+// scale [min, max] for this call only, in place of the S1's rating
+// scale and the scales set with SetItemScale, e.g., when tenants with
+// different scales share a model trained on normalised ratings.
+//
+// If the S1 has a finite rating scale, set with SetRatingScale, ur must
+// be on that scale, and predictions are mapped linearly from it onto
+// [min, max], so that the S1's minimum maps to min and its maximum to
+// max. Otherwise predictions are simply clamped to [min, max]. Either
+// way, predictions are then rounded to the rating step, if any.
+func (s1 *S1) PredictScaled(ur UserRatings, min, max float64) map[int]float64 {
+	o := s1.options
+	o.scales = nil
+	o.min, o.max = min, max
+	if !math.IsInf(s1.min, 0) && !math.IsInf(s1.max, 0) && s1.max > s1.min {
+		o.from = &[2]float64{s1.min, s1.max}
+	}
+	return o.finishAll(s1.estimates(ur, false))
+}
@@ -0,0 +1,26 @@
+package slopeone
+
+import "testing"
+
+func TestPredictScaled(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{{1: 0.5, 2: 0.75, 3: 0.2}})
+	ur := UserRatings{1: 0.5}
+
+	if got := s1.PredictScaled(ur, 0, 0.6); !near(got[2], 0.6) || !near(got[3], 0.2) {
+		t.Errorf("clamped: got %v", got)
+	}
+
+	// Trained on a scale of 0 to 1, predictions of 0.75 and 0.2 map to
+	// 4 and 1.8 on a scale of 1 to 5, and 7.5 and 2 on one of 0 to 10.
+	s1.SetRatingScale(0, 1)
+	if got := s1.PredictScaled(ur, 1, 5); !near(got[2], 4) || !near(got[3], 1.8) {
+		t.Errorf("1 to 5: got %v", got)
+	}
+	if got := s1.PredictScaled(ur, 0, 10); !near(got[2], 7.5) || !near(got[3], 2) {
+		t.Errorf("0 to 10: got %v", got)
+	}
+	if got := s1.Predict(ur); !near(got[2], 0.75) {
+		t.Errorf("unscaled: got %v", got)
+	}
+}
@@ -0,0 +1,72 @@
+package slopeone
+
+// SeedItemFromNeighbors gives newItem, a cold-start item without
+// ratings, estimated rating differences to other items, so that it can
+// be predicted, and predicted from, before anyone has rated it.
+// neighbors are items known to be similar to newItem, e.g., from their
+// content features.
+//
+// newItem's difference to each item j is taken to be the average of
+// the neighbors' differences to j, over the neighbors that have been
+// rated together with j, where a neighbor's difference to itself is
+// 0. Each seeded pair is given a support of 1. This is an
+// approximation: it assumes newItem will be rated as its neighbors are
+// on average, which holds only as far as the neighbors are truly
+// alike.
+//
+// The seeded differences are discarded as soon as ratings of newItem
+// are added, so that they are overridden by the real ratings rather
+// than averaged with them. Seeding an item that has already been rated
+// does nothing, and seeding an item again replaces its seed.
+//
+// Seeds are indistinguishable from real ratings once the S1 is written
+// out, e.g., with MarshalProto, and read back.
+func (s1 *S1) SeedItemFromNeighbors(newItem int, neighbors []int) {
+	s1.unseed(newItem)
+	if _, ok := s1.f[newItem]; ok || s1.counts[newItem] > 0 {
+		return
+	}
+
+	sums, n := make(map[int]float64), make(map[int]int)
+	seen := make(map[int]bool, len(neighbors))
+	for _, nb := range neighbors {
+		if nb == newItem || seen[nb] {
+			continue
+		}
+		seen[nb] = true
+		for j := range s1.f[nb] {
+			if dev, ok := s1.deviation(nb, j); ok {
+				sums[j] += dev
+				n[j]++
+			}
+		}
+	}
+	if len(sums) == 0 {
+		return
+	}
+
+	for j, sum := range sums {
+		dev := sum / float64(n[j])
+		s1.addPair(newItem, j, dev, 1, 1)
+		s1.addPair(j, newItem, -dev, 1, 1)
+	}
+	s1.addPair(newItem, newItem, 0, 1, 1)
+	if s1.seeded == nil {
+		s1.seeded = make(map[int]bool)
+	}
+	s1.seeded[newItem] = true
+	s1.evict()
+}
+
+// unseed removes the seeded differences of item i, if it was seeded
+// with SeedItemFromNeighbors.
+func (s1 *S1) unseed(i int) {
+	if !s1.seeded[i] {
+		return
+	}
+	delete(s1.seeded, i)
+	for j := range s1.f[i] {
+		s1.deletePair(j, i)
+		s1.deletePair(i, j)
+	}
+}
@@ -0,0 +1,39 @@
+package slopeone
+
+import "testing"
+
+func TestSeedItemFromNeighbors(t *testing.T) {
+	s1, want := newFixture(), newFixture()
+	ur := UserRatings{2005: 3}
+	if _, ok := s1.Predict(ur)[99]; ok {
+		t.Fatal("unknown item 99 was predicted")
+	}
+
+	// Items 5513 and 29074 are 1.1 and 0.7 below item 2005, so item 99
+	// is taken to be 0.9 below it. Seeding again replaces the seed.
+	for k := 0; k < 2; k++ {
+		s1.SeedItemFromNeighbors(99, []int{5513, 29074})
+		if got := s1.Predict(ur)[99]; !near(got, 2.1) {
+			t.Errorf("seed %d: got %v, want 2.1", k, got)
+		}
+	}
+	if _, ok := s1.Predict(UserRatings{99: 3})[2005]; !ok {
+		t.Error("item 2005 was not predicted from the seeded item")
+	}
+
+	// Real ratings replace the seed.
+	rated := []UserRatings{{99: 5, 2005: 1}}
+	s1.AddRatings(rated)
+	want.AddRatings(rated)
+	if got, w := s1.Predict(ur), want.Predict(ur); !samePredictions(got, w) {
+		t.Errorf("rated: got %v, want %v", got, w)
+	}
+	if s1.Fingerprint() != want.Fingerprint() || s1.NumPairs() != want.NumPairs() {
+		t.Error("the seed was not discarded")
+	}
+
+	s1.SeedItemFromNeighbors(2005, []int{5513})
+	if s1.Fingerprint() != want.Fingerprint() {
+		t.Error("seeding a rated item changed the S1")
+	}
+}
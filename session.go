@@ -0,0 +1,93 @@
+package slopeone
+
+// PredictionSession incrementally builds predictions for a single user
+// as they rate items one at a time, as in a "rate as you go" interface.
+//
+// A session keeps the running weighted sums that Predict would compute
+// from scratch, so each call to AddRating only visits the items that
+// have been co-rated with the newly rated item.
+//
+// A session reads from the S1 that created it; ratings added to the S1
+// after a session rating was added are not reflected in that rating's
+// contribution.
+type PredictionSession struct {
+	s1 *S1
+
+	// ur holds the ratings the user has provided so far.
+	ur UserRatings
+
+	// est holds the running evidence for the prediction of each target
+	// item.
+	est map[int]estimate
+}
+
+// NewSession returns a *PredictionSession, with no ratings, that
+// predicts using s1.
+func (s1 *S1) NewSession() *PredictionSession {
+	return &PredictionSession{
+		s1:  s1,
+		ur:  make(UserRatings),
+		est: make(map[int]estimate),
+	}
+}
+
+// AddRating records the user's rating for item and updates the
+// predictions for all items co-rated with it.
+//
+// Rating an item that was already rated in the session replaces the
+// earlier rating.
+//
+// When the S1 normalises ratings by z-score, the contributions of all
+// of the user's ratings change with each new rating, so each call
+// visits the items co-rated with any item rated in the session.
+func (ps *PredictionSession) AddRating(item int, rating float64) {
+	if ps.s1.normalization == ZScore {
+		ps.ur[item] = rating
+		ps.est = make(map[int]estimate)
+		sd := ps.s1.spread(ps.ur)
+		for i, r := range ps.ur {
+			ps.apply(i, r, sd, 1)
+		}
+		return
+	}
+
+	if old, ok := ps.ur[item]; ok {
+		ps.apply(item, old, 1, -1)
+	}
+	ps.ur[item] = rating
+	ps.apply(item, rating, 1, 1)
+}
+
+// apply adds (sign 1) or removes (sign -1) the contribution of the
+// user's rating r for item i, with ratings of spread sd, to the running
+// sums.
+func (ps *PredictionSession) apply(i int, r, sd float64, sign int) {
+	for gi := range ps.s1.f[i] {
+		var c estimate
+		if ps.s1.contribute(&c, gi, i, r, sd); c.weight == 0 {
+			continue
+		}
+
+		e := ps.est[gi]
+		e.sum += float64(sign) * c.sum
+		e.weight += float64(sign) * c.weight
+		e.support += sign * c.support
+		e.sources += sign * c.sources
+		e.prior, e.hasPrior = c.prior, c.hasPrior
+		if e.support <= 0 {
+			delete(ps.est, gi)
+			continue
+		}
+		ps.est[gi] = e
+	}
+}
+
+// Get returns the predicted rating for target given the ratings added
+// to the session so far. The second return value is false if target
+// cannot be predicted, or has already been rated in the session.
+func (ps *PredictionSession) Get(target int) (float64, bool) {
+	if _, ok := ps.ur[target]; ok {
+		return 0, false
+	}
+	return ps.s1.finish(target, ps.est[target])
+}
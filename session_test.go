@@ -0,0 +1,29 @@
+package slopeone
+
+import "testing"
+
+func TestSessionMatchesPredict(t *testing.T) {
+	s1 := newFixture()
+	ur := UserRatings{2005: 2.0, 29074: 3.2}
+
+	ps := s1.NewSession()
+	ps.AddRating(2005, 4)
+	ps.AddRating(29074, 3.2)
+	ps.AddRating(2005, 2.0) // replaces the earlier rating
+
+	want := s1.Predict(ur)
+	if len(want) == 0 {
+		t.Fatal("want some predictions")
+	}
+	for i, r := range want {
+		if got, ok := ps.Get(i); !ok || !near(got, r) {
+			t.Errorf("item %d: got %v, %v, want %v", i, got, ok, r)
+		}
+	}
+	if _, ok := ps.Get(2005); ok {
+		t.Error("rated item 2005 was predicted")
+	}
+	if _, ok := ps.Get(99); ok {
+		t.Error("unknown item 99 was predicted")
+	}
+}
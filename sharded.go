@@ -0,0 +1,127 @@
+package slopeone
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// ShardedS1 is a Slope One model that is safe for concurrent use, and
+// supports high write throughput by partitioning items across shards,
+// each with its own lock. Users whose ratings involve disjoint shards
+// can be added in parallel.
+type ShardedS1 struct {
+	shards  []shard
+	ratings atomic.Int64
+}
+
+// shard holds the rating differences of the items assigned to it, to
+// all other items.
+type shard struct {
+	mu sync.RWMutex
+	d  map[int]map[int]float64
+	f  map[int]map[int]int
+}
+
+// NewShardedS1 returns a *ShardedS1 with n shards, ready for use.
+func NewShardedS1(n int) *ShardedS1 {
+	if n < 1 {
+		n = 1
+	}
+	s := &ShardedS1{shards: make([]shard, n)}
+	for k := range s.shards {
+		s.shards[k].d = make(map[int]map[int]float64)
+		s.shards[k].f = make(map[int]map[int]int)
+	}
+	return s
+}
+
+// shard returns the index of the shard item i is assigned to.
+func (s *ShardedS1) shard(i int) int {
+	return int(uint(i) % uint(len(s.shards)))
+}
+
+// lockShards returns the distinct shards the items in ur are assigned
+// to, in ascending order, which is the order they must be locked in to
+// avoid deadlock.
+func (s *ShardedS1) lockShards(ur UserRatings) []int {
+	seen := make(map[int]bool)
+	var shards []int
+	for i := range ur {
+		if k := s.shard(i); !seen[k] {
+			seen[k] = true
+			shards = append(shards, k)
+		}
+	}
+	sort.Ints(shards)
+	return shards
+}
+
+// AddUser adds a single user's ratings to the ShardedS1, locking only
+// the shards that the user's items are assigned to.
+func (s *ShardedS1) AddUser(ur UserRatings) {
+	shards := s.lockShards(ur)
+	for _, k := range shards {
+		s.shards[k].mu.Lock()
+	}
+	defer func() {
+		for _, k := range shards {
+			s.shards[k].mu.Unlock()
+		}
+	}()
+
+	for i1, r1 := range ur {
+		sh := &s.shards[s.shard(i1)]
+		if _, ok := sh.d[i1]; !ok {
+			sh.d[i1] = make(map[int]float64)
+			sh.f[i1] = make(map[int]int)
+		}
+		for i2, r2 := range ur {
+			sh.f[i1][i2]++
+			sh.d[i1][i2] += r1 - r2
+		}
+	}
+	s.ratings.Add(int64(len(ur)))
+}
+
+// AddRatings adds user ratings for sets of items to the ShardedS1, as
+// if by calling AddUser for each user.
+func (s *ShardedS1) AddRatings(users []UserRatings) {
+	for _, ur := range users {
+		s.AddUser(ur)
+	}
+}
+
+// NumRatings returns the total number of individual ratings added to
+// the ShardedS1.
+func (s *ShardedS1) NumRatings() int64 {
+	return s.ratings.Load()
+}
+
+// Predict returns predicted ratings for items the provided user has not
+// yet rated, exactly as S1.Predict does.
+//
+// Only the read locks of the shards that the user's rated items are
+// assigned to are acquired, because the differences between every item
+// and a rated item are the negation of those between the rated item and
+// every item.
+func (s *ShardedS1) Predict(ur UserRatings) map[int]float64 {
+	shards := s.lockShards(ur)
+	for _, k := range shards {
+		s.shards[k].mu.RLock()
+	}
+	defer func() {
+		for _, k := range shards {
+			s.shards[k].mu.RUnlock()
+		}
+	}()
+
+	p, _ := predictRows(ur, func(i int, fn func(gi int, sum float64, n int)) error {
+		sh := &s.shards[s.shard(i)]
+		for gi, n := range sh.f[i] {
+			fn(gi, sh.d[i][gi], n)
+		}
+		return nil
+	})
+	return p
+}
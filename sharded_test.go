@@ -0,0 +1,54 @@
+package slopeone
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestShardedS1(t *testing.T) {
+	s := NewShardedS1(4)
+	var wg sync.WaitGroup
+	for _, ur := range fixture {
+		wg.Add(1)
+		go func(ur UserRatings) {
+			defer wg.Done()
+			s.AddUser(ur)
+			s.Predict(ur)
+		}(ur)
+	}
+	wg.Wait()
+
+	ur := UserRatings{2005: 2.0, 29074: 3.2, -3: 1}
+	if got, want := s.Predict(ur), newFixture().Predict(ur); !samePredictions(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got, want := s.NumRatings(), newFixture().NumRatings(); got != want {
+		t.Errorf("got %d ratings, want %d", got, want)
+	}
+}
+
+// benchmarkAddUser measures the throughput of adding users to s from
+// parallel goroutines. Run the benchmarks with -race to compare
+// throughput under the race detector.
+func benchmarkAddUser(b *testing.B, s interface{ AddUser(UserRatings) }) {
+	users := GenerateRatings(1000, 2000, 0.005, 1)
+	var next atomic.Int64
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			s.AddUser(users[next.Add(1)%int64(len(users))])
+		}
+	})
+}
+
+func BenchmarkAddUserSharded(b *testing.B) {
+	benchmarkAddUser(b, NewShardedS1(64))
+}
+
+// BenchmarkAddUserSingleMutex is the baseline for
+// BenchmarkAddUserSharded: with a single shard, every write takes the
+// one lock, but does the same work per pair of items.
+func BenchmarkAddUserSingleMutex(b *testing.B) {
+	benchmarkAddUser(b, NewShardedS1(1))
+}
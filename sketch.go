@@ -0,0 +1,130 @@
+package slopeone
+
+import "math"
+
+// SketchS1 is an approximate Slope One model for very large item
+// spaces, which stores the rating differences of item pairs in
+// count-min sketches of fixed size rather than in exact maps.
+//
+// A count-min sketch of width w and depth d is d rows of w cells. Each
+// pair of items is hashed to one cell in each row, and adding a
+// co-rating of the pair adds to every one of those cells, so cells are
+// shared by colliding pairs. The support of a pair is estimated as the
+// minimum of its cells, which is never less than the true support and,
+// with probability 1 - e^-d, exceeds it by at most e·N/w, where N is
+// the total number of co-ratings added. The sum of the pair's rating
+// differences is read from the same row as that minimum, the row least
+// affected by collisions, so the deviation of a pair is also
+// approximate wherever its cells are shared.
+//
+// A SketchS1 needs 16·w·d bytes for its sketches, regardless of the
+// number of pairs, plus memory proportional to the number of distinct
+// items. Wider sketches reduce the error from collisions; deeper
+// sketches make large errors less likely.
+type SketchS1 struct {
+	width, depth int
+
+	// counts and sums hold depth rows of width cells, row after row.
+	counts []int64
+	sums   []float64
+
+	// items holds every item that has been rated.
+	items map[int]struct{}
+}
+
+// NewSketchS1 returns a *SketchS1 with sketches of the given width and
+// depth, ready for use. Widths and depths less than 1 are treated as 1.
+func NewSketchS1(width, depth int) *SketchS1 {
+	if width < 1 {
+		width = 1
+	}
+	if depth < 1 {
+		depth = 1
+	}
+	return &SketchS1{
+		width:  width,
+		depth:  depth,
+		counts: make([]int64, width*depth),
+		sums:   make([]float64, width*depth),
+		items:  make(map[int]struct{}),
+	}
+}
+
+// cell returns the index of the cell in row k that the pair i1, i2,
+// where i1 < i2, is hashed to.
+func (sk *SketchS1) cell(k, i1, i2 int) int {
+	// splitmix64 finalizer over the pair, seeded per row.
+	h := uint64(i1)*0x9e3779b97f4a7c15 ^ uint64(i2) ^ uint64(k+1)*0xbf58476d1ce4e5b9
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return k*sk.width + int(h%uint64(sk.width))
+}
+
+// AddRatings adds user ratings for sets of items to the SketchS1.
+func (sk *SketchS1) AddRatings(users []UserRatings) {
+	for _, user := range users {
+		for i1, r1 := range user {
+			sk.items[i1] = struct{}{}
+			for i2, r2 := range user {
+				if i1 >= i2 {
+					continue
+				}
+				for k := 0; k < sk.depth; k++ {
+					c := sk.cell(k, i1, i2)
+					sk.counts[c]++
+					sk.sums[c] += r1 - r2
+				}
+			}
+		}
+	}
+}
+
+// pair returns the estimated support of the pair i1, i2, and the
+// estimated sum of the differences between the ratings of i1 and i2.
+func (sk *SketchS1) pair(i1, i2 int) (n int64, sum float64) {
+	sign := 1.0
+	if i1 > i2 {
+		i1, i2, sign = i2, i1, -1
+	}
+
+	n = math.MaxInt64
+	for k := 0; k < sk.depth; k++ {
+		if c := sk.cell(k, i1, i2); sk.counts[c] < n {
+			n, sum = sk.counts[c], sk.sums[c]
+		}
+	}
+	return n, sign * sum
+}
+
+// Predict returns approximate predicted ratings for items the provided
+// user has not yet rated, as S1.Predict does, using the estimated
+// supports and deviations of pairs.
+//
+// As the sketches cannot be enumerated, every item that has been rated
+// is considered, so Predict takes time proportional to the number of
+// items multiplied by the number of items the user rated.
+func (sk *SketchS1) Predict(ur UserRatings) map[int]float64 {
+	p := make(map[int]float64)
+	for j := range sk.items {
+		if _, ok := ur[j]; ok {
+			continue
+		}
+
+		var sum, weight float64
+		for i, r := range ur {
+			n, d := sk.pair(j, i)
+			if n == 0 {
+				continue
+			}
+			sum += d + float64(n)*r
+			weight += float64(n)
+		}
+		if weight > 0 {
+			p[j] = sum / weight
+		}
+	}
+	return p
+}
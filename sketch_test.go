@@ -0,0 +1,28 @@
+package slopeone
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSketchS1(t *testing.T) {
+	data := GenerateRatings(100, 40, 0.3, 5)
+	s1 := NewS1()
+	s1.AddRatings(data)
+	sk := NewSketchS1(16384, 4)
+	sk.AddRatings(data)
+
+	var worst float64
+	for k, ur := range data[:10] {
+		want, got := s1.Predict(ur), sk.Predict(ur)
+		if len(got) != len(want) {
+			t.Fatalf("user %d: got %d predictions, want %d", k, len(got), len(want))
+		}
+		for i := range want {
+			worst = math.Max(worst, math.Abs(got[i]-want[i]))
+		}
+	}
+	if worst > 0.05 {
+		t.Errorf("got a worst error of %v", worst)
+	}
+}
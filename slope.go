@@ -11,8 +11,15 @@
 // Slope One is an incredibly simple item-item collaborative filtering
 // algorithm, which uses user-item ratings to provide a model to predict
 // users' ratings for items they have yet to rate.
+//
+// The functions and methods whose names end in WithRand draw their
+// randomness from a caller's *rand.Rand. It is used without locking, so
+// it must not be used concurrently by other goroutines unless its
+// source is safe for concurrent use.
 package slopeone
 
+import "math"
+
 // This type is just for semantic intent.
 
 // UserRatings is a set of item ratings belonging to a user.
@@ -20,11 +27,14 @@ type UserRatings map[int]float64
 
 // S1 implements the Slope One algorithm.
 type S1 struct {
-	// d maintains a mapping between items and their rating differences
-	// to other items. For examples, given item1 with a rating of 3.5
-	// and item2 with a rating of 4.5, one could add the following to
-	// the d:
-	//	d["item1"]["item2"] = -1.0
+	// d maintains a mapping between items and the sum of their rating
+	// differences to other items. For examples, given item1 with a
+	// rating of 3.5 and item2 with a rating of 4.5, one could add the
+	// following to the d:
+	//	d["item1"]["item2"] += -1.0
+	//
+	// Sums are normalised by f when the model is read, which allows
+	// ratings to be added and removed at any time.
 	d map[int]map[int]float64
 
 	// f maintains a mapping between items and the number of times
@@ -33,46 +43,401 @@ type S1 struct {
 	// calculated, then the following would be added to f:
 	//	f["item1"]["item2"]++
 	f map[int]map[int]int
+
+	// w maintains the total weight of the differences summed in d. It
+	// is nil until weighted ratings are added, in which case every
+	// difference contributed a weight of 1, and f is used instead.
+	w map[int]map[int]float64
+
+	// sq maintains the sum of the squares of the differences summed in
+	// d, each weighted as in w. It is nil unless variance weighting is
+	// enabled.
+	sq map[int]map[int]float64
+
+	// comp maintains the compensation of each sum in d, the negation
+	// of the low-order part of the sum lost to rounding, for Kahan
+	// summation. It is nil unless stable summation is enabled.
+	comp map[int]map[int]float64
+
+	// samples maintains the individual differences between each pair of
+	// distinct items, sorted. It is nil unless trimming is enabled.
+	samples map[int]map[int][]float64
+
+	// counts maintains the number of users that have rated each item,
+	// and sums the sum of their ratings.
+	counts map[int]int
+	sums   map[int]float64
+
+	// ratings maintains the total number of ratings in the S1.
+	ratings int64
+
+	// pairs maintains the number of entries in f between distinct
+	// items. As f holds both i1 vs i2 and i2 vs i1 this is twice the
+	// number of distinct item pairs.
+	pairs int
+
+	// fp maintains the fingerprint of the S1, the sum of the hashes of
+	// its pairs.
+	fp uint64
+
+	// gen is incremented whenever the pairs of items, or the settings
+	// affecting predictions, change.
+	gen uint64
+
+	// cache, if not nil, holds recent predictions.
+	cache *predictCache
+
+	// options holds the settings that adjust how the S1 is trained
+	// and makes predictions.
+	options
+
+	// users maintains the last-known ratings of users added via
+	// UpsertUser, keyed by user id.
+	users map[int]UserRatings
+
+	// meta holds the metadata set with SetItemMeta, keyed by item.
+	meta map[int]any
+
+	// seeded holds the items given estimated differences by
+	// SeedItemFromNeighbors that have not yet been rated.
+	seeded map[int]bool
+
+	// observed holds copies of the ratings added with AddRatingsAt,
+	// in order of the time they were observed, so that they can be
+	// removed by ExpireBefore.
+	observed []observation
+
+	// updates maintains the number of users' ratings added or removed
+	// since the S1 was last compacted automatically.
+	updates int
 }
 
 // NewS1 returns an *S1 ready for use.
 func NewS1() *S1 {
 	return &S1{
-		d: make(map[int]map[int]float64),
-		f: make(map[int]map[int]int),
+		d:      make(map[int]map[int]float64),
+		f:      make(map[int]map[int]int),
+		counts: make(map[int]int),
+		sums:   make(map[int]float64),
+		users:  make(map[int]UserRatings),
+
+		options: defaultOptions(),
 	}
 }
 
 // AddRatings adds user ratings for sets of items to the S1.
 // Ratings for added items will be taken into consideration in future
 // predictions.
-func (s1 *S1) AddRatings(users []UserRatings) {
+//
+// While the S1's items are frozen with FreezeItems, ratings of unknown
+// items are skipped. AddRatings returns the number of ratings skipped.
+func (s1 *S1) AddRatings(users []UserRatings) int {
+	var skipped int
 	for _, user := range users {
-		// For each item and rating generate the difference in rating
-		// between this one and all other items.
-		for i1, r1 := range user {
-			if _, ok := s1.d[i1]; !ok {
-				s1.d[i1] = make(map[int]float64)
-				s1.f[i1] = make(map[int]int)
-			}
+		user, n := s1.known(user)
+		skipped += n
+		s1.update(user, 1)
+	}
+	s1.evict()
+	s1.updated(len(users))
+	return skipped
+}
 
-			// Update the frequency of i1 vs i2 and the total rating
-			// difference observed.
-			for i2, r2 := range user {
-				s1.f[i1][i2]++
-				s1.d[i1][i2] += (r1 - r2)
-			}
+// RemoveRatings removes user ratings, previously added with
+// AddRatings, from the S1. Removed ratings will no longer be taken
+// into consideration in future predictions.
+//
+// Each UserRatings must exactly match one that was added; removing
+// ratings that were never added leaves the S1 meaningless.
+func (s1 *S1) RemoveRatings(users []UserRatings) {
+	for _, user := range users {
+		user, _ := s1.known(user)
+		s1.update(user, -1)
+	}
+	s1.updated(len(users))
+}
+
+// UpsertUser adds the ratings of the user identified by id to the S1.
+//
+// If ratings for the same id were previously added via UpsertUser then
+// they are removed first, so that re-submitting a user, e.g. when
+// replaying events, never double counts their ratings. While the S1's
+// items are frozen, ratings of unknown items are skipped.
+func (s1 *S1) UpsertUser(id int, ur UserRatings) {
+	if old, ok := s1.users[id]; ok {
+		s1.update(old, -1)
+	}
+
+	cp := make(UserRatings, len(ur))
+	for i, r := range ur {
+		if s1.isKnown(i) {
+			cp[i] = r
+		}
+	}
+	s1.users[id] = cp
+	s1.update(cp, 1)
+	s1.evict()
+	s1.updated(1)
+}
+
+// extend adds a user's rating r of item to the S1, where the user's
+// other ratings, profile, have already been added, and records the
+// rating in profile. If the user had already rated item, their previous
+// ratings are replaced.
+func (s1 *S1) extend(profile UserRatings, item int, r float64) {
+	if !s1.isKnown(item) {
+		return
+	}
+
+	// A z-score normalised user's rating differences all change with
+	// each new rating, as do those of a user whose influence is capped.
+	if _, ok := profile[item]; ok || s1.normalization == ZScore ||
+		(s1.maxItemsPerUser > 0 && len(profile) >= s1.maxItemsPerUser) {
+		s1.update(profile, -1)
+		profile[item] = r
+		s1.update(profile, 1)
+		return
+	}
+
+	s1.unseed(item)
+	s1.count(item, r, 1)
+	for j, rj := range profile {
+		diff := s1.in(r) - s1.in(rj)
+		s1.addPair(item, j, diff, 1, 1)
+		s1.addPair(j, item, -diff, 1, 1)
+	}
+	s1.addPair(item, item, 0, 1, 1)
+	profile[item] = r
+}
+
+// update adds (sign 1) or removes (sign -1) the contribution of a
+// single user's ratings to the S1.
+func (s1 *S1) update(user UserRatings, sign int) {
+	s1.updateRows(user, sign, nil)
+}
+
+// updateRows is like update, but if rows is not nil, only updates the
+// rating counts of, and differences from, the items rows reports true
+// for, while still normalising and weighting the user's ratings as a
+// whole.
+func (s1 *S1) updateRows(user UserRatings, sign int, rows func(i int) bool) {
+	// For each item and rating generate the difference in rating
+	// between this one and all other items.
+	for i := range user {
+		s1.unseed(i)
+	}
+	sd := s1.spread(user)
+	w := s1.userWeight(len(user))
+	if w != 1 {
+		s1.initWeights()
+	}
+	for i1, r1 := range user {
+		if rows != nil && !rows(i1) {
+			continue
+		}
+		s1.count(i1, r1, sign)
+		for i2, r2 := range user {
+			s1.addPair(i1, i2, (s1.in(r1)-s1.in(r2))/sd, w, sign)
+		}
+	}
+}
+
+// count adds (sign 1) or removes (sign -1) a single user's rating r of
+// item i from the S1's rating counts.
+func (s1 *S1) count(i int, r float64, sign int) {
+	s1.ratings += int64(sign)
+	s1.sums[i] += float64(sign) * r
+	if s1.counts[i] += sign; s1.counts[i] <= 0 {
+		delete(s1.counts, i)
+		delete(s1.sums, i)
+	}
+}
+
+// addPair adds (sign 1) or removes (sign -1) a single observed rating
+// difference diff between items i1 and i2, with weight w.
+func (s1 *S1) addPair(i1, i2 int, diff, w float64, sign int) {
+	_, seen := s1.f[i1][i2]
+	if !seen {
+		if sign < 0 {
+			return
+		}
+		if i1 != i2 {
+			s1.pairs++
+		}
+	}
+
+	if _, ok := s1.d[i1]; !ok {
+		s1.d[i1] = make(map[int]float64)
+		s1.f[i1] = make(map[int]int)
+		if s1.w != nil {
+			s1.w[i1] = make(map[int]float64)
+		}
+		if s1.sq != nil {
+			s1.sq[i1] = make(map[int]float64)
+		}
+		if s1.comp != nil {
+			s1.comp[i1] = make(map[int]float64)
+		}
+		if s1.samples != nil {
+			s1.samples[i1] = make(map[int][]float64)
 		}
 	}
 
-	// Normalise the difference in ratings for each pair of items, by
-	// the number of times each item-pair have had their differences
-	// calculated.
-	for i1, diffs := range s1.d {
-		for i2 := range diffs {
-			diffs[i2] /= float64(s1.f[i1][i2])
+	// Update the frequency of i1 vs i2 and the total rating
+	// difference observed.
+	s1.gen++
+	s1.unhash(i1, i2)
+	dev := s1.d[i1][i2] / s1.weight(i1, i2)
+	s1.f[i1][i2] += sign
+	s1.addSum(i1, i2, float64(sign)*w*diff)
+	if s1.w != nil {
+		s1.w[i1][i2] += float64(sign) * w
+	}
+	if s1.emaAlpha > 0 && seen {
+		// The sum is kept such that normalising it by the weight
+		// gives the moving average.
+		if sign > 0 {
+			dev = s1.emaAlpha*diff + (1-s1.emaAlpha)*dev
+		}
+		s1.d[i1][i2] = dev * s1.weight(i1, i2)
+		if s1.comp != nil {
+			s1.comp[i1][i2] = 0
 		}
 	}
+	if s1.sq != nil {
+		s1.sq[i1][i2] += float64(sign) * w * diff * diff
+	}
+	if s1.samples != nil && i1 != i2 {
+		s1.samples[i1][i2] = updateSamples(s1.samples[i1][i2], diff, sign)
+	}
+	s1.rehash(i1, i2)
+	if s1.f[i1][i2] <= 0 {
+		s1.deletePair(i1, i2)
+	}
+}
+
+// pairStats holds everything the S1 knows about the differences
+// between a pair of items.
+type pairStats struct {
+	sum     float64   // the sum of the weighted differences
+	n       int       // the number of differences
+	w       float64   // the total weight of the differences
+	sq      float64   // the sum of the weighted squared differences
+	samples []float64 // the sorted differences, if trimming is enabled
+}
+
+// stats returns the pairStats of i1 and i2.
+func (s1 *S1) stats(i1, i2 int) pairStats {
+	return pairStats{
+		sum:     s1.d[i1][i2],
+		n:       s1.f[i1][i2],
+		w:       s1.weight(i1, i2),
+		sq:      s1.sq[i1][i2],
+		samples: s1.samples[i1][i2],
+	}
+}
+
+// addRaw adds the differences between i1 and i2 described by p to the
+// S1.
+func (s1 *S1) addRaw(i1, i2 int, p pairStats) {
+	if p.n <= 0 {
+		return
+	}
+
+	if _, ok := s1.d[i1]; !ok {
+		s1.d[i1] = make(map[int]float64)
+		s1.f[i1] = make(map[int]int)
+		if s1.w != nil {
+			s1.w[i1] = make(map[int]float64)
+		}
+		if s1.sq != nil {
+			s1.sq[i1] = make(map[int]float64)
+		}
+		if s1.comp != nil {
+			s1.comp[i1] = make(map[int]float64)
+		}
+		if s1.samples != nil {
+			s1.samples[i1] = make(map[int][]float64)
+		}
+	}
+	if _, ok := s1.f[i1][i2]; !ok && i1 != i2 {
+		s1.pairs++
+	}
+
+	s1.gen++
+	s1.unhash(i1, i2)
+	s1.addSum(i1, i2, p.sum)
+	s1.f[i1][i2] += p.n
+	if s1.w != nil {
+		s1.w[i1][i2] += p.w
+	}
+	if s1.sq != nil {
+		s1.sq[i1][i2] += p.sq
+	}
+	if s1.samples != nil && len(p.samples) > 0 {
+		s1.samples[i1][i2] = mergeSamples(s1.samples[i1][i2], p.samples)
+	}
+	s1.rehash(i1, i2)
+}
+
+// deletePair removes the pair i1, i2 from the S1, removing i1
+// altogether if it no longer has any pairs.
+func (s1 *S1) deletePair(i1, i2 int) {
+	s1.gen++
+	s1.unhash(i1, i2)
+	if _, ok := s1.f[i1][i2]; ok && i1 != i2 {
+		s1.pairs--
+	}
+	delete(s1.d[i1], i2)
+	delete(s1.f[i1], i2)
+	if s1.w != nil {
+		delete(s1.w[i1], i2)
+	}
+	if s1.sq != nil {
+		delete(s1.sq[i1], i2)
+	}
+	if s1.comp != nil {
+		delete(s1.comp[i1], i2)
+	}
+	if s1.samples != nil {
+		delete(s1.samples[i1], i2)
+	}
+
+	if len(s1.f[i1]) == 0 {
+		delete(s1.d, i1)
+		delete(s1.f, i1)
+		if s1.w != nil {
+			delete(s1.w, i1)
+		}
+		if s1.sq != nil {
+			delete(s1.sq, i1)
+		}
+		if s1.comp != nil {
+			delete(s1.comp, i1)
+		}
+		if s1.samples != nil {
+			delete(s1.samples, i1)
+		}
+	}
+}
+
+// weight returns the total weight of the differences between i1 and
+// i2.
+func (s1 *S1) weight(i1, i2 int) float64 {
+	if s1.w == nil {
+		return float64(s1.f[i1][i2])
+	}
+	return s1.w[i1][i2]
+}
+
+// deviation returns the average rating difference between i1 and i2,
+// and false if the difference has never been calculated.
+func (s1 *S1) deviation(i1, i2 int) (float64, bool) {
+	w := s1.weight(i1, i2)
+	if w == 0 {
+		return 0, false
+	}
+	return s1.pairSum(i1, i2, w) / w, true
 }
 
 // Predict returns predicted ratings for items the provided user has not
@@ -82,39 +447,250 @@ func (s1 *S1) AddRatings(users []UserRatings) {
 // Items the user has rated are not included in the returned
 // UserPredictions.
 func (s1 *S1) Predict(ur UserRatings) map[int]float64 {
-	p, f := make(map[int]float64), make(map[int]int)
-	var gf int
-	// For each item-rating the user has rated we will compare it to
-	// all global item-ratings, and update our prediction of unrated
-	// items for the user.
+	if s1.cache != nil {
+		return s1.cache.predict(s1, ur)
+	}
+	return s1.predict(ur, false)
+}
+
+// PredictAll is like Predict, but also returns predictions for the
+// items the user has rated. The prediction for a rated item is based on
+// the user's ratings of their other items, and can be compared to their
+// actual rating, e.g., to find items they rated lower than expected.
+func (s1 *S1) PredictAll(ur UserRatings) map[int]float64 {
+	return s1.predict(ur, true)
+}
+
+// PredictExcluding is like Predict, but also excludes the dismissed
+// items from the returned predictions, e.g., recommendations that the
+// user has explicitly dismissed without rating them.
+func (s1 *S1) PredictExcluding(ur UserRatings, dismissed []int) map[int]float64 {
+	est := s1.estimates(ur, false)
+	for _, i := range dismissed {
+		delete(est, i)
+	}
+	return s1.finishAll(est)
+}
+
+// PredictSlices is like Predict, but takes the user's ratings as a
+// sparse vector of parallel slices, where ratings[k] is the user's
+// rating of items[k], avoiding the need to build a UserRatings.
+//
+// The items must be distinct. PredictSlices panics if items and
+// ratings have different lengths.
+func (s1 *S1) PredictSlices(items []int, ratings []float64) map[int]float64 {
+	if len(items) != len(ratings) {
+		panic("slopeone: PredictSlices called with mismatched items and ratings")
+	}
+
+	est := make(map[int]estimate)
+	sd := s1.spreadOf(ratings)
+	for k, i := range items {
+		s1.scan(est, i, ratings[k], sd)
+	}
+	for _, i := range items {
+		delete(est, i)
+	}
+	return s1.finishAll(est)
+}
+
+// PredictItem returns the predicted rating of a single item for the
+// provided user, as Predict would. The second return value is false if
+// the item cannot be predicted, or if the user has already rated it.
+func (s1 *S1) PredictItem(ur UserRatings, item int) (float64, bool) {
+	if _, ok := ur[item]; ok {
+		return 0, false
+	}
+
+	var e estimate
+	sd := s1.spread(ur)
 	for i, r := range ur {
-		for gi, gr := range s1.d {
-			// If items have never been analysed or we will want to
-			// remove them from the predicted set anyway, then move on.
-			if gf = s1.f[gi][i]; gf == 0 || gi == i {
-				continue
-			}
+		s1.contribute(&e, item, i, r, sd)
+	}
+	return s1.finish(item, e)
+}
 
-			// Update our prediction of the unrated item's rating for
-			// the user according to the global rating difference
-			// between the user's rated item (i) and the other item
-			// we're looking at (gi). This difference gives us a
-			// direction to modify they user's providing rating for i
-			// by, in order to predict their rating of gi.
-			p[gi] += (float64(gf) * (gr[i] + r))
-			f[gi] += gf
+// estimate accumulates the evidence for the predicted rating of an
+// item.
+type estimate struct {
+	// sum is the sum of the predictions of the item's rating made from
+	// each of the user's rated items, each weighted by the weight of
+	// the pair of items.
+	sum float64
+
+	// weight is the total weight of the predictions in sum.
+	weight float64
+
+	// support is the number of co-ratings the predictions are based
+	// on.
+	support int
+
+	// sources is the number of the user's rated items the predictions
+	// are made from.
+	sources int
+
+	// prior, if hasPrior, is the item's mean rating, as used in the
+	// model's calculations, that the prediction is shrunk towards.
+	prior    float64
+	hasPrior bool
+}
+
+// contribute adds the prediction of item gi's rating, made from the
+// user's rating r of item i, to e. sd is the spread of the user's
+// ratings.
+func (s1 *S1) contribute(e *estimate, gi, i int, r, sd float64) {
+	if r < s1.floor || gi == i {
+		return
+	}
+	if gf := s1.weight(gi, i); gf != 0 && s1.f[gi][i] >= s1.minSupport {
+		v, _ := s1.PairVariance(gi, i)
+		e.add(&s1.options, s1.pairSum(gi, i, gf)*sd, gf, s1.f[gi][i], v, s1.in(r))
+		s1.setPrior(e, gi)
+	}
+}
+
+// setPrior sets the prior of e, the estimate of item gi's rating, if
+// predictions are shrunk and it is not already set.
+func (s1 *S1) setPrior(e *estimate, gi int) {
+	if s1.shrinkage == 0 || e.hasPrior {
+		return
+	}
+	if mean, ok := s1.ItemMean(gi); ok {
+		e.prior, e.hasPrior = s1.in(mean), true
+	}
+}
+
+// add adds a prediction to e, made from a user's rating r of an item
+// whose rating differences to the predicted item sum to sum, with total
+// weight gf and variance v, over n co-ratings.
+func (e *estimate) add(o *options, sum, gf float64, n int, v, r float64) {
+	e.support += n
+	e.sources++
+	if o.weightFunc != nil || v != 0 {
+		wt := gf
+		if o.weightFunc != nil {
+			wt = o.weightFunc(n)
 		}
+		wt /= 1 + v
+		e.sum += wt * (sum/gf + r)
+		e.weight += wt
+		return
+	}
+
+	// As sum is the sum of gf differences, weighting the normalised
+	// difference by gf is simply sum.
+	e.sum += sum + gf*r
+	e.weight += gf
+}
+
+// overflowed reports whether the evidence in e is not finite, e.g.,
+// because a user provided an enormous rating, so that it cannot make a
+// meaningful prediction.
+func (e estimate) overflowed() bool {
+	x := e.sum / e.weight
+	return e.weight != 0 && (math.IsInf(x, 0) || math.IsNaN(x))
+}
+
+// finish returns the predicted rating of item from e, and false if e
+// does not hold enough evidence to make a prediction, or has
+// overflowed.
+func (o *options) finish(item int, e estimate) (float64, bool) {
+	if e.weight == 0 || e.sources < o.minContributors || e.overflowed() {
+		return 0, false
 	}
 
-	// Normalise each predicted rating, and remove ones that were in the
-	// set of provided ratings.
-	for i := range p {
-		p[i] /= float64(f[i])
-		for j := range ur {
-			if i == j {
-				delete(p, j)
+	x := e.sum / e.weight
+	if o.shrinkage > 0 && e.hasPrior {
+		n := float64(e.support)
+		x = (n*x + o.shrinkage*e.prior) / (n + o.shrinkage)
+	}
+	r := o.out(x)
+	min, max := o.scale(item)
+	if o.from != nil {
+		lo, hi := o.from[0], o.from[1]
+		r = math.Max(lo, math.Min(hi, r))
+		r = min + (r-lo)/(hi-lo)*(max-min)
+	}
+	r = math.Max(min, math.Min(max, r))
+	if o.step > 0 {
+		r = math.Round(r/o.step) * o.step
+	}
+	return r, true
+}
+
+// predict returns predicted ratings based on the provided user's
+// ratings, including predictions for the items they have rated if
+// rated is true.
+func (s1 *S1) predict(ur UserRatings, rated bool) map[int]float64 {
+	return s1.finishAll(s1.estimates(ur, rated))
+}
+
+// finishAll returns the predicted ratings from est, omitting items
+// that cannot be predicted.
+func (o *options) finishAll(est map[int]estimate) map[int]float64 {
+	p := make(map[int]float64, len(est))
+	for i, e := range est {
+		if r, ok := o.finish(i, e); ok {
+			p[i] = r
+			if o.observer != nil {
+				o.observer(i, r, e.support)
 			}
 		}
 	}
 	return p
 }
+
+// estimates returns the evidence for the predicted ratings of each
+// item based on the provided user's ratings, including the items they
+// have rated if rated is true.
+func (s1 *S1) estimates(ur UserRatings, rated bool) map[int]estimate {
+	est := make(map[int]estimate)
+	sd := s1.spread(ur)
+	for i, r := range ur {
+		s1.scan(est, i, r, sd)
+	}
+
+	// Remove predictions for items that were in the set of provided
+	// ratings unless they are wanted.
+	if !rated {
+		for i := range ur {
+			delete(est, i)
+		}
+	}
+	return est
+}
+
+// scan adds the predictions of every item's rating, made from the
+// user's rating r of item i, to est. sd is the spread of the user's
+// ratings.
+func (s1 *S1) scan(est map[int]estimate, i int, r, sd float64) {
+	if r < s1.floor {
+		return
+	}
+	r = s1.in(r)
+
+	// We will compare the user's item-rating to all global
+	// item-ratings, and update our prediction of unrated items for the
+	// user.
+	var gf float64
+	for gi := range s1.d {
+		// If items have never been analysed, have been analysed too
+		// few times, or we are comparing an item to itself, then move
+		// on.
+		if gf = s1.weight(gi, i); gf == 0 || gi == i || s1.f[gi][i] < s1.minSupport {
+			continue
+		}
+
+		// Update our prediction of the unrated item's rating for the
+		// user according to the global rating difference between the
+		// user's rated item (i) and the other item we're looking at
+		// (gi). This difference gives us a direction to modify they
+		// user's providing rating for i by, in order to predict their
+		// rating of gi.
+		e := est[gi]
+		v, _ := s1.PairVariance(gi, i)
+		e.add(&s1.options, s1.pairSum(gi, i, gf)*sd, gf, s1.f[gi][i], v, r)
+		s1.setPrior(&e, gi)
+		est[gi] = e
+	}
+}
@@ -0,0 +1,168 @@
+package slopeone
+
+import (
+	"math"
+	"testing"
+)
+
+// fixture holds the users' ratings from the README, and one further
+// user who co-rated two items unknown to the other users.
+var fixture = []UserRatings{
+	{2005: 2.4, 5513: 1.3, 13035: 2.0},
+	{5513: 4, 359602: 5, 13035: 1.5, 29074: 4},
+	{29074: 4.3, 359602: 2.5, 2005: 5},
+	{1: 3, 2: 4, 2005: 1},
+}
+
+// newFixture returns an *S1 trained on fixture.
+func newFixture() *S1 {
+	s1 := NewS1()
+	s1.AddRatings(fixture)
+	return s1
+}
+
+// near reports whether a and b are equal, but for rounding.
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// samePredictions reports whether a and b predict the same items with
+// near-equal ratings.
+func samePredictions(a, b map[int]float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, r := range a {
+		if s, ok := b[i]; !ok || !near(r, s) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPredictREADME(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings(fixture[:3])
+
+	got := s1.Predict(UserRatings{2005: 2.0, 29074: 3.2})
+	want := map[int]float64{359602: 1.7, 5513: 2.1, 13035: 1.2}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i, r := range want {
+		if math.Abs(got[i]-r) > 0.05 {
+			t.Errorf("item %d: got %.3f, want %.1f", i, got[i], r)
+		}
+	}
+}
+
+func TestPredictExact(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{
+		{1: 1, 2: 1.5},
+		{1: 2, 2: 3, 3: 2},
+	})
+
+	// Item 2 is rated 1 above item 1 on average (0.5 and 1), item 3 is
+	// 0 above item 1 from one user.
+	got := s1.Predict(UserRatings{1: 2})
+	want := map[int]float64{2: 2.75, 3: 2}
+	if !samePredictions(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestPredictNoRatings(t *testing.T) {
+	if got := newFixture().Predict(UserRatings{}); len(got) != 0 {
+		t.Errorf("got %v, want no predictions", got)
+	}
+	if got := NewS1().Predict(UserRatings{1: 4}); len(got) != 0 {
+		t.Errorf("got %v, want no predictions", got)
+	}
+}
+
+func TestUpsertUser(t *testing.T) {
+	s1 := NewS1()
+	s1.UpsertUser(1, UserRatings{1: 1, 2: 5})
+	s1.UpsertUser(2, UserRatings{1: 2, 2: 3, 3: 4})
+	s1.UpsertUser(1, UserRatings{1: 4, 2: 2})
+
+	want := NewS1()
+	want.AddRatings([]UserRatings{{1: 4, 2: 2}, {1: 2, 2: 3, 3: 4}})
+
+	ur := UserRatings{1: 3}
+	if got, w := s1.Predict(ur), want.Predict(ur); !samePredictions(got, w) {
+		t.Errorf("got %v, want %v", got, w)
+	}
+	if got := s1.NumRatings(); got != 5 {
+		t.Errorf("got %d ratings, want 5", got)
+	}
+}
+
+func TestAddRatingsIncrementally(t *testing.T) {
+	s1 := NewS1()
+	for _, ur := range fixture {
+		s1.AddRatings([]UserRatings{ur})
+	}
+
+	ur := UserRatings{2005: 2.0, 29074: 3.2}
+	if got, want := s1.Predict(ur), newFixture().Predict(ur); !samePredictions(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestPredictAll(t *testing.T) {
+	s1 := newFixture()
+	ur := UserRatings{2005: 2.0, 29074: 3.2}
+
+	all := s1.PredictAll(ur)
+	p := s1.Predict(ur)
+	if len(all) != len(p)+len(ur) {
+		t.Fatalf("got %v, want predictions for %v and the rated items", all, p)
+	}
+	for i, r := range p {
+		if !near(all[i], r) {
+			t.Errorf("item %d: got %v, want %v", i, all[i], r)
+		}
+	}
+
+	// Each rated item is predicted from the user's other ratings.
+	for i := range ur {
+		others := make(UserRatings)
+		for j, r := range ur {
+			if j != i {
+				others[j] = r
+			}
+		}
+		if want := s1.Predict(others)[i]; !near(all[i], want) {
+			t.Errorf("rated item %d: got %v, want %v", i, all[i], want)
+		}
+	}
+}
+
+func TestPredictSlices(t *testing.T) {
+	s1 := newFixture()
+	got := s1.PredictSlices([]int{2005, 29074}, []float64{2.0, 3.2})
+	if want := s1.Predict(UserRatings{2005: 2.0, 29074: 3.2}); !samePredictions(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("mismatched slices did not panic")
+		}
+	}()
+	s1.PredictSlices([]int{1}, nil)
+}
+
+func TestPredictExcluding(t *testing.T) {
+	s1 := newFixture()
+	ur := UserRatings{2005: 2}
+
+	want := s1.Predict(ur)
+	delete(want, 5513)
+	delete(want, 1)
+	if got := s1.PredictExcluding(ur, []int{5513, 1, 999}); !samePredictions(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,42 @@
+package slopeone
+
+// SetStableSummation enables or disables stable summation of rating
+// differences. Each pair of items' sum of differences is normally
+// accumulated by plain floating-point addition, so rounding error
+// grows with the number of differences summed, and depends on the
+// order they are added in. With stable summation, sums are accumulated
+// with Kahan (compensated) summation, which tracks the rounding error
+// of each pair's sum and corrects for it as further differences are
+// added, so the error stays close to that of a single addition however
+// many differences are summed.
+//
+// Stable summation needs an additional value to be stored for every
+// pair of items. It may be enabled at any time, taking effect for
+// subsequently added differences; disabling it discards the tracked
+// errors.
+func (s1 *S1) SetStableSummation(enabled bool) {
+	switch {
+	case enabled && s1.comp == nil:
+		s1.comp = make(map[int]map[int]float64, len(s1.d))
+		for i1 := range s1.d {
+			s1.comp[i1] = make(map[int]float64)
+		}
+	case !enabled:
+		s1.comp = nil
+	}
+}
+
+// addSum adds x to the sum of the differences between i1 and i2,
+// which must have a row in the S1, compensating for rounding error if
+// stable summation is enabled.
+func (s1 *S1) addSum(i1, i2 int, x float64) {
+	if s1.comp == nil {
+		s1.d[i1][i2] += x
+		return
+	}
+
+	y := x - s1.comp[i1][i2]
+	t := s1.d[i1][i2] + y
+	s1.comp[i1][i2] = (t - s1.d[i1][i2]) - y
+	s1.d[i1][i2] = t
+}
@@ -0,0 +1,37 @@
+package slopeone
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestSetStableSummation(t *testing.T) {
+	// Many users rate item 1 a little above item 2, by amounts that are
+	// not exact in binary.
+	var data []UserRatings
+	for k := 0; k < 200000; k++ {
+		data = append(data, UserRatings{1: 1.1 + float64(k%7)*0.01, 2: 1})
+	}
+	sum := new(big.Float).SetPrec(200)
+	for _, ur := range data {
+		sum.Add(sum, new(big.Float).SetPrec(200).SetFloat64(ur[1]-ur[2]))
+	}
+	want, _ := sum.Quo(sum, big.NewFloat(float64(len(data)))).Float64()
+
+	naive, stable := NewS1(), NewS1()
+	stable.SetStableSummation(true)
+	naive.AddRatings(data)
+	stable.AddRatings(data)
+	ur := UserRatings{2: 0}
+	naiveErr := math.Abs(naive.Predict(ur)[1] - want)
+	stableErr := math.Abs(stable.Predict(ur)[1] - want)
+	if !(stableErr < naiveErr) || stableErr > 1e-15 {
+		t.Errorf("got an error of %v, want less than the naive sum's %v", stableErr, naiveErr)
+	}
+
+	stable.RemoveRatings(data[1:])
+	if got := stable.Predict(ur)[1]; !near(got, 0.1) {
+		t.Errorf("after removal: got %v, want 0.1", got)
+	}
+}
@@ -0,0 +1,87 @@
+package slopeone
+
+import "fmt"
+
+// ExtractSubModel returns a new S1, with the same settings as s1,
+// holding only the pairs of items where at least one of the pair is in
+// items, e.g., to deploy only the part of a model relevant to a set of
+// promoted items.
+//
+// Predictions of the items in items made by the sub-model are the same
+// as those made by s1.
+func (s1 *S1) ExtractSubModel(items []int) *S1 {
+	keep := make(map[int]bool, len(items))
+	for _, i := range items {
+		keep[i] = true
+	}
+
+	sub := s1.withOptions()
+	for i1, freqs := range s1.f {
+		for i2 := range freqs {
+			if keep[i1] || keep[i2] {
+				sub.addRaw(i1, i2, s1.stats(i1, i2))
+			}
+		}
+	}
+
+	for i := range sub.f {
+		if n, ok := s1.counts[i]; ok {
+			sub.counts[i], sub.sums[i] = n, s1.sums[i]
+			sub.ratings += int64(n)
+		}
+	}
+	return sub
+}
+
+// Anonymize returns a copy of the S1 with every item id replaced
+// according to mapping, e.g., to share a model without revealing
+// internal item ids. The relationships between items are preserved, so
+// the copy's predictions correspond to s1's under the mapping.
+//
+// An error is returned if any item in the S1 is missing from mapping,
+// or if two items would be mapped to the same id.
+func (s1 *S1) Anonymize(mapping map[int]int) (*S1, error) {
+	seen := make(map[int]int, len(s1.counts))
+	check := func(i int) error {
+		to, ok := mapping[i]
+		if !ok {
+			return fmt.Errorf("slopeone: item %d is missing from mapping", i)
+		}
+		if prev, ok := seen[to]; ok && prev != i {
+			return fmt.Errorf("slopeone: items %d and %d are both mapped to %d", prev, i, to)
+		}
+		seen[to] = i
+		return nil
+	}
+	for i := range s1.f {
+		if err := check(i); err != nil {
+			return nil, err
+		}
+	}
+	for i := range s1.counts {
+		if err := check(i); err != nil {
+			return nil, err
+		}
+	}
+
+	anon := s1.withOptions()
+	for i1, freqs := range s1.f {
+		for i2 := range freqs {
+			anon.addRaw(mapping[i1], mapping[i2], s1.stats(i1, i2))
+		}
+	}
+	for i, n := range s1.counts {
+		anon.counts[mapping[i]], anon.sums[mapping[i]] = n, s1.sums[i]
+	}
+	anon.ratings = s1.ratings
+
+	if s1.scales != nil {
+		anon.scales = make(map[int][2]float64, len(s1.scales))
+		for i, sc := range s1.scales {
+			if to, ok := mapping[i]; ok {
+				anon.scales[to] = sc
+			}
+		}
+	}
+	return anon, nil
+}
@@ -0,0 +1,59 @@
+package slopeone
+
+import "testing"
+
+func TestExtractSubModel(t *testing.T) {
+	data := GenerateRatings(100, 50, 0.2, 9)
+	s1 := NewS1()
+	s1.AddRatings(data)
+	s1.SetRatingScale(1, 5)
+
+	items := []int{1, 5, 9, 30}
+	sub := s1.ExtractSubModel(items)
+	if sub.NumPairs() >= s1.NumPairs() {
+		t.Errorf("sub-model has %d of %d pairs", sub.NumPairs(), s1.NumPairs())
+	}
+	for k, ur := range data[:10] {
+		want, got := s1.Predict(ur), sub.Predict(ur)
+		for _, i := range items {
+			w, wok := want[i]
+			g, gok := got[i]
+			if wok != gok || !near(g, w) {
+				t.Errorf("user %d, item %d: got %v, %v, want %v, %v", k, i, g, gok, w, wok)
+			}
+		}
+	}
+}
+
+func TestAnonymize(t *testing.T) {
+	s1 := newFixture()
+	mapping := make(map[int]int)
+	_, items := s1.DenseMatrix()
+	for k, i := range items {
+		mapping[i] = 1000 + k
+	}
+
+	anon, err := s1.Anonymize(mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := s1.Predict(UserRatings{2005: 2})
+	got := anon.Predict(UserRatings{mapping[2005]: 2})
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v remapped", got, want)
+	}
+	for i, r := range want {
+		if !near(got[mapping[i]], r) {
+			t.Errorf("item %d as %d: got %v, want %v", i, mapping[i], got[mapping[i]], r)
+		}
+	}
+
+	mapping[1] = mapping[2]
+	if _, err := s1.Anonymize(mapping); err == nil {
+		t.Error("got nil error for a mapping that is not one-to-one")
+	}
+	delete(mapping, 1)
+	if _, err := s1.Anonymize(mapping); err == nil {
+		t.Error("got nil error for a mapping missing an item")
+	}
+}
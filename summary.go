@@ -0,0 +1,61 @@
+package slopeone
+
+import (
+	"math"
+	"sort"
+)
+
+// PredSummary summarises the distribution of a user's predicted
+// ratings.
+type PredSummary struct {
+	Count          int
+	Mean, Min, Max float64
+
+	// P25, Median, P75 and P90 are percentiles of the predicted
+	// ratings, linearly interpolated between the closest predictions.
+	P25, Median, P75, P90 float64
+}
+
+// PredictionSummary returns a summary of the provided user's predicted
+// ratings, as returned by Predict. If the user has no predictions, the
+// zero PredSummary is returned.
+func (s1 *S1) PredictionSummary(ur UserRatings) PredSummary {
+	est := s1.estimates(ur, false)
+	ratings := make([]float64, 0, len(est))
+	ps := PredSummary{Min: math.Inf(1), Max: math.Inf(-1)}
+	var sum float64
+	for i, e := range est {
+		r, ok := s1.finish(i, e)
+		if !ok {
+			continue
+		}
+		ratings = append(ratings, r)
+		sum += r
+		ps.Min = math.Min(ps.Min, r)
+		ps.Max = math.Max(ps.Max, r)
+	}
+	if len(ratings) == 0 {
+		return PredSummary{}
+	}
+
+	ps.Count = len(ratings)
+	ps.Mean = sum / float64(len(ratings))
+	sort.Float64s(ratings)
+	ps.P25 = percentile(ratings, 0.25)
+	ps.Median = percentile(ratings, 0.5)
+	ps.P75 = percentile(ratings, 0.75)
+	ps.P90 = percentile(ratings, 0.9)
+	return ps
+}
+
+// percentile returns the q-th quantile of the sorted, non-empty
+// values, linearly interpolating between the closest values.
+func percentile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lo := int(pos)
+	if lo == len(sorted)-1 {
+		return sorted[lo]
+	}
+	frac := pos - float64(lo)
+	return sorted[lo] + frac*(sorted[lo+1]-sorted[lo])
+}
@@ -0,0 +1,21 @@
+package slopeone
+
+import "testing"
+
+func TestPredictionSummary(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{{1: 1, 2: 2, 3: 3, 4: 4, 5: 5}})
+
+	// The predictions are 2, 3, 4 and 5.
+	got := s1.PredictionSummary(UserRatings{1: 1})
+	want := PredSummary{Count: 4, Mean: 3.5, Min: 2, Max: 5, Median: 3.5, P25: 2.75, P75: 4.25, P90: 4.7}
+	if got.Count != want.Count || !near(got.Mean, want.Mean) || !near(got.Min, want.Min) ||
+		!near(got.Max, want.Max) || !near(got.Median, want.Median) || !near(got.P25, want.P25) ||
+		!near(got.P75, want.P75) || !near(got.P90, want.P90) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if got := s1.PredictionSummary(UserRatings{9: 1}); got != (PredSummary{}) {
+		t.Errorf("got %+v, want the zero PredSummary", got)
+	}
+}
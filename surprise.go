@@ -0,0 +1,45 @@
+package slopeone
+
+import (
+	"math"
+	"sort"
+)
+
+// Surprise is a user's rating of an item that differs markedly from
+// the rating the model predicts for it.
+type Surprise struct {
+	Item      int
+	Actual    float64
+	Predicted float64
+
+	// Delta is Actual - Predicted, so it is negative for an item the
+	// user rated lower than predicted.
+	Delta float64
+}
+
+// SurprisingRatings returns the items the provided user has rated
+// whose ratings differ from their predictions by more than threshold,
+// e.g., to flag mis-rates or changes of taste. Each rated item is
+// predicted from the user's other ratings, as by PredictAll; items that
+// cannot be predicted are never surprising.
+//
+// The surprises are sorted by descending magnitude of Delta, breaking
+// ties by ascending item.
+func (s1 *S1) SurprisingRatings(ur UserRatings, threshold float64) []Surprise {
+	var out []Surprise
+	for i, p := range s1.PredictAll(ur) {
+		r, ok := ur[i]
+		if !ok || math.Abs(r-p) <= threshold {
+			continue
+		}
+		out = append(out, Surprise{Item: i, Actual: r, Predicted: p, Delta: r - p})
+	}
+
+	sort.Slice(out, func(a, b int) bool {
+		if da, db := math.Abs(out[a].Delta), math.Abs(out[b].Delta); da != db {
+			return da > db
+		}
+		return out[a].Item < out[b].Item
+	})
+	return out
+}
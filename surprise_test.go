@@ -0,0 +1,19 @@
+package slopeone
+
+import "testing"
+
+func TestSurprisingRatings(t *testing.T) {
+	var data []UserRatings
+	for k := 0; k < 5; k++ {
+		data = append(data, UserRatings{1: 4, 2: 4, 3: 4})
+	}
+	s1 := NewS1()
+	s1.AddRatings(data)
+
+	// Items are always rated alike, so only the rating of 1 is far
+	// from its prediction.
+	got := s1.SurprisingRatings(UserRatings{1: 4, 2: 4, 3: 1}, 2)
+	if len(got) != 1 || got[0].Item != 3 || !near(got[0].Predicted, 4) || !near(got[0].Delta, -3) {
+		t.Errorf("got %+v, want item 3 predicted 4, 3 above its rating", got)
+	}
+}
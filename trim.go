@@ -0,0 +1,91 @@
+package slopeone
+
+import (
+	"math"
+	"sort"
+)
+
+// SetTrimFraction makes the deviation of each pair of items a trimmed
+// mean, robust to outlying co-ratings: the fraction f of the pair's
+// highest differences, and f of its lowest, are discarded before the
+// rest are averaged. At least one difference, the median, is always
+// kept. An f of 0 or less, the default, disables trimming.
+//
+// Trimming must be enabled before any ratings are added. It requires
+// every individual rating difference to be retained, rather than only
+// their sum, so a trimmed model needs memory proportional to the total
+// number of co-ratings, i.e., the sum over users of the square of the
+// number of items they rated, which is typically far more than the
+// number of item pairs. Each prediction also visits every retained
+// difference of the pairs it uses.
+//
+// Trimmed means are not weighted, so ratings added with
+// AddWeightedRatings count equally towards them. Disabling trimming
+// discards the retained differences.
+func (s1 *S1) SetTrimFraction(f float64) {
+	if !(f > 0) {
+		s1.trim, s1.samples = 0, nil
+		s1.gen++
+		s1.fp = s1.fingerprint()
+		return
+	}
+	s1.trim = f
+	if s1.samples == nil {
+		s1.samples = make(map[int]map[int][]float64)
+	}
+	s1.gen++
+
+	// The deviations of pairs whose differences are retained change
+	// with the fraction trimmed.
+	s1.fp = s1.fingerprint()
+}
+
+// pairSum returns the sum of the differences between i1 and i2, which
+// have total weight w, as used in predictions. If the pair's
+// differences are trimmed, this is the trimmed mean multiplied by w.
+func (s1 *S1) pairSum(i1, i2 int, w float64) float64 {
+	samples := s1.samples[i1][i2]
+	if len(samples) == 0 {
+		return s1.d[i1][i2]
+	}
+
+	k := int(math.Floor(s1.trim * float64(len(samples))))
+	if max := (len(samples) - 1) / 2; k > max {
+		k = max
+	}
+	var sum float64
+	for _, v := range samples[k : len(samples)-k] {
+		sum += v
+	}
+	return sum / float64(len(samples)-2*k) * w
+}
+
+// updateSamples adds (sign 1) or removes (sign -1) the difference diff
+// to or from the sorted samples, returning the updated slice.
+func updateSamples(samples []float64, diff float64, sign int) []float64 {
+	k := sort.SearchFloat64s(samples, diff)
+	if sign < 0 {
+		if k < len(samples) && samples[k] == diff {
+			samples = append(samples[:k], samples[k+1:]...)
+		}
+		return samples
+	}
+	samples = append(samples, 0)
+	copy(samples[k+1:], samples[k:])
+	samples[k] = diff
+	return samples
+}
+
+// mergeSamples returns the sorted union of the sorted samples a and b.
+func mergeSamples(a, b []float64) []float64 {
+	out := make([]float64, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		if a[0] <= b[0] {
+			out, a = append(out, a[0]), a[1:]
+		} else {
+			out, b = append(out, b[0]), b[1:]
+		}
+	}
+	out = append(out, a...)
+	return append(out, b...)
+}
@@ -0,0 +1,36 @@
+package slopeone
+
+import "testing"
+
+func TestSetTrimFraction(t *testing.T) {
+	// Nine users rate item 1 one above item 2; an outlier rates it four
+	// below.
+	var users []UserRatings
+	for k := 0; k < 9; k++ {
+		users = append(users, UserRatings{1: 4, 2: 3})
+	}
+	users = append(users, UserRatings{1: 1, 2: 5})
+	ur := UserRatings{2: 3}
+
+	plain := NewS1()
+	plain.AddRatings(users)
+	if got := plain.Predict(ur)[1]; near(got, 4) {
+		t.Fatalf("untrimmed: got %v, want the outlier to count", got)
+	}
+
+	s1 := NewS1()
+	s1.SetTrimFraction(0.1)
+	s1.AddRatings(users)
+	if got := s1.Predict(ur)[1]; !near(got, 4) {
+		t.Errorf("trimmed: got %v, want 4", got)
+	}
+	if got := s1.ExtractSubModel([]int{1}).Predict(ur)[1]; !near(got, 4) {
+		t.Errorf("sub-model: got %v, want 4", got)
+	}
+
+	// With only two differences, 1 and -4, none are trimmed.
+	s1.RemoveRatings(users[:8])
+	if got := s1.Predict(ur)[1]; !near(got, 1.5) {
+		t.Errorf("after removal: got %v, want 1.5", got)
+	}
+}
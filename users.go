@@ -0,0 +1,65 @@
+package slopeone
+
+import (
+	"math"
+	"sort"
+)
+
+// UserScore is a user along with a score, the meaning of which depends
+// on how it was produced.
+type UserScore struct {
+	User  int
+	Score float64
+}
+
+// SimilarUsers returns the k candidates, which maps user ids to their
+// ratings, most similar to the target user, sorted by descending
+// similarity, with ties broken by ascending user id.
+//
+// Similarity is measured through the item model rather than by
+// comparing ratings directly, so users need not have rated the same
+// items to be similar. The target's ratings of every item are
+// predicted, as by PredictAll, and a candidate's similarity is
+//
+//	1 / (1 + rmse)
+//
+// where rmse is the root mean squared error of those predictions
+// against the candidate's actual ratings, over the items they rated
+// that can be predicted. A candidate who rates items exactly as the
+// target is predicted to has a similarity of 1. Candidates with no
+// predictable items are omitted. A k of 0 or less returns nil.
+func (s1 *S1) SimilarUsers(target UserRatings, candidates map[int]UserRatings, k int) []UserScore {
+	if k <= 0 {
+		return nil
+	}
+
+	p := s1.PredictAll(target)
+
+	var scores []UserScore
+	for user, ur := range candidates {
+		var sq float64
+		var n int
+		for i, r := range ur {
+			if pr, ok := p[i]; ok {
+				sq += (pr - r) * (pr - r)
+				n++
+			}
+		}
+		if n == 0 {
+			continue
+		}
+		rmse := math.Sqrt(sq / float64(n))
+		scores = append(scores, UserScore{User: user, Score: 1 / (1 + rmse)})
+	}
+
+	sort.Slice(scores, func(a, b int) bool {
+		if scores[a].Score != scores[b].Score {
+			return scores[a].Score > scores[b].Score
+		}
+		return scores[a].User < scores[b].User
+	})
+	if len(scores) > k {
+		scores = scores[:k]
+	}
+	return scores
+}
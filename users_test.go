@@ -0,0 +1,29 @@
+package slopeone
+
+import "testing"
+
+func TestSimilarUsers(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{{1: 5, 2: 4, 3: 1}, {1: 4, 2: 3, 3: 0}})
+
+	// Candidate 10 rates items 2 and 3 just as the user is predicted
+	// to; candidate 11 the opposite. Candidate 12 shares nothing.
+	got := s1.SimilarUsers(UserRatings{1: 4.5}, map[int]UserRatings{
+		10: {2: 3.5, 3: 0.5},
+		11: {2: 1, 3: 5},
+		12: {9: 1},
+	}, 5)
+	if len(got) != 2 || got[0].User != 10 || !near(got[0].Score, 1) || got[1].User != 11 || got[1].Score >= got[0].Score {
+		t.Errorf("got %v, want 10 then 11", got)
+	}
+}
+
+func TestSimilarUsersNone(t *testing.T) {
+	s1 := newFixture()
+	candidates := map[int]UserRatings{1: fixture[1], 2: fixture[2]}
+	for _, k := range []int{0, -1} {
+		if got := s1.SimilarUsers(fixture[0], candidates, k); got != nil {
+			t.Errorf("k %d: got %v, want nil", k, got)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package slopeone
+
+// SetVarianceWeighting enables or disables variance weighting. It must
+// be enabled before any ratings are added, as the variance of pairs
+// trained beforehand is not known.
+//
+// With variance weighting the S1 also tracks the variance of the
+// rating differences observed for each pair of items, available from
+// PairVariance. A pair whose co-raters disagree about the difference
+// between the items is less reliable than one whose co-raters agree,
+// so during prediction the weight of each pair, which is normally its
+// support (or the weight returned by the function set with
+// SetWeightFunc), is divided by
+//
+//	1 + variance
+//
+// A pair whose differences are all the same keeps its full weight.
+//
+// Disabling variance weighting discards the tracked variances.
+func (s1 *S1) SetVarianceWeighting(enabled bool) {
+	switch {
+	case enabled && s1.sq == nil:
+		s1.sq = make(map[int]map[int]float64)
+	case !enabled:
+		s1.sq = nil
+	}
+	s1.gen++
+}
+
+// PairVariance returns the variance of the rating differences observed
+// between items a and b. The second return value is false if variance
+// weighting is not enabled, or the items have never been rated
+// together.
+func (s1 *S1) PairVariance(a, b int) (float64, bool) {
+	if s1.sq == nil {
+		return 0, false
+	}
+	gf := s1.weight(a, b)
+	if gf == 0 {
+		return 0, false
+	}
+
+	mean := s1.d[a][b] / gf
+	v := s1.sq[a][b]/gf - mean*mean
+	if v < 0 {
+		// Rounding error when every difference is the same.
+		v = 0
+	}
+	return v, true
+}
@@ -0,0 +1,37 @@
+package slopeone
+
+import "testing"
+
+func TestVarianceWeighting(t *testing.T) {
+	s1 := NewS1()
+	s1.SetVarianceWeighting(true)
+	s1.AddRatings([]UserRatings{
+		{1: 5, 2: 4, 3: 1},
+		{1: 3, 2: 2, 3: 5},
+	})
+
+	// Both users rate item 1 one above item 2, but disagree about item
+	// 3, rating item 1 4 above and 2 below it: a mean of 1 and a
+	// variance of 9.
+	if v, ok := s1.PairVariance(1, 2); !ok || !near(v, 0) {
+		t.Errorf("consistent pair: got %v, %v, want 0", v, ok)
+	}
+	if v, ok := s1.PairVariance(1, 3); !ok || !near(v, 9) {
+		t.Errorf("conflicting pair: got %v, %v, want 9", v, ok)
+	}
+
+	// Item 2 predicts item 1 to be 5 with a weight of 2, and item 3
+	// predicts 2 with a weight of 2 / (1 + 9).
+	want := (2*5.0 + 0.2*2) / 2.2
+	if got := s1.Predict(UserRatings{2: 4, 3: 1})[1]; !near(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	s1.RemoveRatings([]UserRatings{{1: 3, 2: 2, 3: 5}})
+	if v, ok := s1.PairVariance(1, 3); !ok || !near(v, 0) {
+		t.Errorf("after removal: got %v, %v, want 0", v, ok)
+	}
+	if _, ok := NewS1().PairVariance(1, 2); ok {
+		t.Error("got a variance without variance weighting")
+	}
+}
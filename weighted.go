@@ -0,0 +1,79 @@
+package slopeone
+
+// WeightedRating is a rating along with the confidence in it. For
+// example, an explicit star rating may be given a higher weight than a
+// rating inferred from behaviour.
+type WeightedRating struct {
+	Value  float64
+	Weight float64
+}
+
+// AddWeightedRatings adds user ratings, each carrying its own weight,
+// to the S1.
+//
+// The rating difference between a pair of items rated by a user is
+// weighted by the smaller of the two ratings' weights, so a difference
+// is only as trusted as the least confident rating it involves. Normal
+// ratings, as added by AddRatings, have a weight of 1. Differences with
+// a weight of zero or less are ignored.
+//
+// Weights affect both the average difference between a pair of items
+// and how heavily that pair counts towards a prediction. The weights
+// of a user with more ratings than the limit set with
+// SetMaxItemsPerUser are scaled down as they are for AddRatings. While
+// the S1's items are frozen, ratings of unknown items are skipped.
+func (s1 *S1) AddWeightedRatings(users []map[int]WeightedRating) {
+	s1.initWeights()
+
+	for _, user := range users {
+		if s1.frozenItems {
+			known := make(map[int]WeightedRating, len(user))
+			for i, r := range user {
+				if s1.isKnown(i) {
+					known[i] = r
+				}
+			}
+			user = known
+		}
+
+		values := make([]float64, 0, len(user))
+		for i, r := range user {
+			s1.unseed(i)
+			values = append(values, r.Value)
+		}
+		sd := s1.spreadOf(values)
+		uw := s1.userWeight(len(user))
+
+		for i1, r1 := range user {
+			s1.count(i1, r1.Value, 1)
+			for i2, r2 := range user {
+				w := r1.Weight
+				if r2.Weight < w {
+					w = r2.Weight
+				}
+				if w <= 0 {
+					continue
+				}
+				s1.addPair(i1, i2, (s1.in(r1.Value)-s1.in(r2.Value))/sd, w*uw, 1)
+			}
+		}
+	}
+	s1.evict()
+	s1.updated(len(users))
+}
+
+// initWeights starts maintaining the weights of the S1's differences,
+// if it is not already, with every difference so far having a weight
+// of 1.
+func (s1 *S1) initWeights() {
+	if s1.w != nil {
+		return
+	}
+	s1.w = make(map[int]map[int]float64, len(s1.f))
+	for i1, freqs := range s1.f {
+		s1.w[i1] = make(map[int]float64, len(freqs))
+		for i2, n := range freqs {
+			s1.w[i1][i2] = float64(n)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package slopeone
+
+import "testing"
+
+func TestWeightedRatingsDominate(t *testing.T) {
+	s1 := NewS1()
+	s1.AddRatings([]UserRatings{{1: 1, 2: 2}})
+	s1.AddWeightedRatings([]map[int]WeightedRating{
+		{1: {Value: 3, Weight: 9}, 2: {Value: 1, Weight: 10}},
+	})
+
+	// The difference between items 2 and 1 is 1 with weight 1 and -2
+	// with weight 9, the lower weight of the pair's ratings, so their
+	// weighted average is (1 - 18) / 10.
+	want := 3 + (1.0-18)/10
+	if got := s1.Predict(UserRatings{1: 3})[2]; !near(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	ps := s1.NewSession()
+	ps.AddRating(1, 3)
+	if got, _ := ps.Get(2); !near(got, want) {
+		t.Errorf("session: got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,47 @@
+package slopeone
+
+import "math"
+
+// WhatIf answers "how would the prediction for target change if the
+// user's ratings changed?". It returns the predicted rating of target
+// for the user's ratings ur, and for ur updated with changes, which may
+// alter existing ratings or add new ones.
+//
+// Both predictions are made in a single pass over the ratings. Either
+// prediction is NaN if target cannot be predicted from the respective
+// ratings, or is rated in them.
+func (s1 *S1) WhatIf(ur UserRatings, changes UserRatings, target int) (before, after float64) {
+	sdb, sda := s1.spread(ur), 1.0
+	if s1.normalization == ZScore {
+		changed := make(UserRatings, len(ur)+len(changes))
+		for i, r := range ur {
+			changed[i] = r
+		}
+		for i, r := range changes {
+			changed[i] = r
+		}
+		sda = s1.spread(changed)
+	}
+
+	var eb, ea estimate
+	for i, r := range ur {
+		s1.contribute(&eb, target, i, r, sdb)
+		if _, ok := changes[i]; !ok {
+			s1.contribute(&ea, target, i, r, sda)
+		}
+	}
+	for i, r := range changes {
+		s1.contribute(&ea, target, i, r, sda)
+	}
+
+	_, rated := ur[target]
+	_, changed := changes[target]
+	before, after = math.NaN(), math.NaN()
+	if r, ok := s1.finish(target, eb); ok && !rated {
+		before = r
+	}
+	if r, ok := s1.finish(target, ea); ok && !rated && !changed {
+		after = r
+	}
+	return before, after
+}
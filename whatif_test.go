@@ -0,0 +1,34 @@
+package slopeone
+
+import "testing"
+
+func TestWhatIf(t *testing.T) {
+	s1 := newFixture()
+	ur := UserRatings{2005: 2.0, 29074: 3.2}
+	changes := UserRatings{2005: 5, 13035: 1}
+	changed := UserRatings{2005: 5, 29074: 3.2, 13035: 1}
+
+	before, after := s1.WhatIf(ur, changes, 359602)
+	if want, _ := s1.PredictItem(ur, 359602); !near(before, want) {
+		t.Errorf("before: got %v, want %v", before, want)
+	}
+	if want, _ := s1.PredictItem(changed, 359602); !near(after, want) {
+		t.Errorf("after: got %v, want %v", after, want)
+	}
+	if want := s1.Predict(changed)[359602]; !near(after, want) {
+		t.Errorf("after: got %v, want Predict's %v", after, want)
+	}
+}
+
+func TestPredictItem(t *testing.T) {
+	s1 := newFixture()
+	ur := UserRatings{2005: 2.0, 29074: 3.2}
+	for i, want := range s1.Predict(ur) {
+		if got, ok := s1.PredictItem(ur, i); !ok || !near(got, want) {
+			t.Errorf("item %d: got %v, %v, want %v", i, got, ok, want)
+		}
+	}
+	if _, ok := s1.PredictItem(ur, 2005); ok {
+		t.Error("rated item was predicted")
+	}
+}
@@ -0,0 +1,132 @@
+package slopeone
+
+import (
+	"sort"
+	"time"
+)
+
+// WindowedS1 is an S1 that only retains the influence of the most
+// recently added batches of ratings, for sliding-window
+// recommendations.
+//
+// Only ratings added with WindowedS1.AddRatings are windowed; ratings
+// added via the embedded S1's other methods are retained forever.
+type WindowedS1 struct {
+	*S1
+
+	window int
+
+	// batches holds copies of the batches currently in the window,
+	// oldest first, so that they can be removed from the S1.
+	batches [][]UserRatings
+}
+
+// NewWindowedS1 returns a *WindowedS1 ready for use, that retains the
+// most recent window batches.
+func NewWindowedS1(window int) *WindowedS1 {
+	if window < 1 {
+		window = 1
+	}
+	return &WindowedS1{S1: NewS1(), window: window}
+}
+
+// AddRatings adds a batch of user ratings to the WindowedS1. If this
+// takes the number of batches over the window, the oldest batch is
+// removed, so it no longer influences predictions.
+//
+// As with S1.AddRatings, ratings of unknown items are skipped while
+// the items are frozen, and the number skipped is returned.
+func (ws *WindowedS1) AddRatings(users []UserRatings) int {
+	batch := make([]UserRatings, len(users))
+	var skipped int
+	for k, ur := range users {
+		ur, n := ws.known(ur)
+		skipped += n
+		batch[k] = make(UserRatings, len(ur))
+		for i, r := range ur {
+			batch[k][i] = r
+		}
+	}
+
+	ws.S1.AddRatings(batch)
+	ws.batches = append(ws.batches, batch)
+	for len(ws.batches) > ws.window {
+		ws.S1.RemoveRatings(ws.batches[0])
+		ws.batches[0] = nil
+		ws.batches = ws.batches[1:]
+	}
+	return skipped
+}
+
+// RemoveItem removes item from the WindowedS1, as S1.RemoveItem does,
+// and forgets its ratings in the batches in the window, so that when
+// their batch leaves the window exactly what they contribute is
+// removed.
+func (ws *WindowedS1) RemoveItem(item int) {
+	for _, batch := range ws.batches {
+		for _, ur := range batch {
+			ws.forget(ur, item)
+		}
+	}
+	ws.S1.RemoveItem(item)
+}
+
+// observation is a batch of user ratings observed at a single time.
+type observation struct {
+	t     time.Time
+	users []UserRatings
+}
+
+// AddRatingsAt adds user ratings observed at time t to the S1, as
+// AddRatings does, and records them so that they can later be removed
+// by ExpireBefore, for windowing by time rather than by batch as
+// WindowedS1 does. Ratings need not be added in the order they were
+// observed.
+//
+// A copy of every rating added with AddRatingsAt is retained until it
+// is expired, so the S1 needs memory for every rating in the window in
+// addition to that of the model itself.
+//
+// As with AddRatings, ratings of unknown items are skipped while the
+// items are frozen, and the number skipped is returned.
+func (s1 *S1) AddRatingsAt(users []UserRatings, t time.Time) int {
+	batch := make([]UserRatings, len(users))
+	var skipped int
+	for k, ur := range users {
+		ur, n := s1.known(ur)
+		skipped += n
+		batch[k] = make(UserRatings, len(ur))
+		for i, r := range ur {
+			batch[k][i] = r
+		}
+	}
+	s1.AddRatings(batch)
+
+	// Batches observed at the same time are kept in the order they were
+	// added.
+	k := sort.Search(len(s1.observed), func(k int) bool {
+		return s1.observed[k].t.After(t)
+	})
+	s1.observed = append(s1.observed, observation{})
+	copy(s1.observed[k+1:], s1.observed[k:])
+	s1.observed[k] = observation{t: t, users: batch}
+	return skipped
+}
+
+// ExpireBefore removes every rating added with AddRatingsAt that was
+// observed before t, as RemoveRatings does, so that those ratings no
+// longer influence predictions. Ratings added by other means are never
+// expired.
+func (s1 *S1) ExpireBefore(t time.Time) {
+	k := sort.Search(len(s1.observed), func(k int) bool {
+		return !s1.observed[k].t.Before(t)
+	})
+	for _, o := range s1.observed[:k] {
+		s1.RemoveRatings(o.users)
+	}
+	n := copy(s1.observed, s1.observed[k:])
+	for j := n; j < len(s1.observed); j++ {
+		s1.observed[j] = observation{}
+	}
+	s1.observed = s1.observed[:n]
+}
@@ -0,0 +1,114 @@
+package slopeone
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowedS1(t *testing.T) {
+	const window = 3
+	ws := NewWindowedS1(window)
+
+	// The two oldest batches rate items the newer batches never rate,
+	// and disagree with them about item 2.
+	ws.AddRatings([]UserRatings{{1: 1, 2: 1, 9: 5}})
+	ws.AddRatings([]UserRatings{{1: 1, 2: 1, 8: 5}})
+	want := NewS1()
+	for k := 0; k < window; k++ {
+		batch := []UserRatings{{1: 1, 2: float64(3 + k)}}
+		ws.AddRatings(batch)
+		want.AddRatings(batch)
+	}
+
+	ur := UserRatings{1: 1}
+	got := ws.Predict(ur)
+	if w := want.Predict(ur); !samePredictions(got, w) {
+		t.Errorf("got %v, want %v", got, w)
+	}
+	for _, i := range []int{8, 9} {
+		if _, ok := got[i]; ok {
+			t.Errorf("item %d from an expired batch was predicted", i)
+		}
+	}
+	if got := ws.NumPairs(); got != 1 {
+		t.Errorf("got %d pairs, want 1", got)
+	}
+	if got := ws.ItemRatingCount(1); got != window {
+		t.Errorf("got %d ratings of item 1, want %d", got, window)
+	}
+}
+
+func TestExpireBefore(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	s1 := NewS1()
+	s1.AddRatingsAt(fixture[2:], t0.Add(2*time.Hour))
+	s1.AddRatingsAt(fixture[:2], t0)
+	s1.AddRatingsAt(fixture[:1], t0.Add(time.Hour))
+	s1.ExpireBefore(t0.Add(time.Hour))
+
+	// Only the batch observed at t0 is expired, although it was not the
+	// first added.
+	want := NewS1()
+	want.AddRatings(fixture[2:])
+	want.AddRatings(fixture[:1])
+	if got, w := s1.Predict(fixture[1]), want.Predict(fixture[1]); !samePredictions(got, w) {
+		t.Errorf("got %v, want %v", got, w)
+	}
+	if got, w := s1.NumRatings(), want.NumRatings(); got != w {
+		t.Errorf("got %d ratings, want %d", got, w)
+	}
+
+	s1.ExpireBefore(t0.Add(3 * time.Hour))
+	if s1.NumRatings() != 0 || s1.NumPairs() != 0 {
+		t.Errorf("got %d ratings and %d pairs, want none", s1.NumRatings(), s1.NumPairs())
+	}
+}
+
+func TestRemoveItemThenExpire(t *testing.T) {
+	users := []UserRatings{{1: 1, 2: 3, 3: 5}, {1: 2, 2: 2, 3: 5}, {1: 4, 2: 1}}
+	reduced := []UserRatings{{1: 1, 2: 3}, {1: 2, 2: 2}, {1: 4, 2: 1}}
+	kept := []UserRatings{{1: 1, 2: 2, 4: 3}}
+
+	// A z-score normalised user's differences between items 1 and 2
+	// depend on their rating of item 3, so removing it changes them.
+	t0 := time.Unix(1000, 0)
+	s1, want := NewS1(), NewS1()
+	for _, s := range []*S1{s1, want} {
+		s.SetNormalization(ZScore)
+		s.AddRatings(kept)
+	}
+	s1.AddRatingsAt(users, t0)
+	s1.RemoveItem(3)
+	want.AddRatings(reduced)
+	ur := UserRatings{1: 3}
+	if got, w := s1.Predict(ur), want.Predict(ur); !samePredictions(got, w) {
+		t.Errorf("got %v, want %v", got, w)
+	}
+
+	// Expiring the ratings leaves only those that were kept.
+	s1.ExpireBefore(t0.Add(time.Hour))
+	want.RemoveRatings(reduced)
+	if got, w := s1.Predict(ur), want.Predict(ur); !samePredictions(got, w) {
+		t.Errorf("expired: got %v, want %v", got, w)
+	}
+	if s1.NumRatings() != 3 || s1.NumPairs() != 3 || s1.ItemRatingCount(3) != 0 {
+		t.Errorf("expired: got %d ratings and %d pairs, want 3 and 3", s1.NumRatings(), s1.NumPairs())
+	}
+
+	// Likewise for a user whose influence is capped.
+	ws, wantWindowed := NewWindowedS1(1), NewS1()
+	for _, s := range []*S1{ws.S1, wantWindowed} {
+		s.SetMaxItemsPerUser(2)
+		s.AddRatings(kept)
+	}
+	ws.AddRatings([]UserRatings{{1: 5, 2: 1, 3: 3, 5: 2}})
+	ws.RemoveItem(3)
+	ws.AddRatings([]UserRatings{{4: 1, 6: 2}})
+	wantWindowed.AddRatings([]UserRatings{{4: 1, 6: 2}})
+	if got, w := ws.Predict(ur), wantWindowed.Predict(ur); !samePredictions(got, w) {
+		t.Errorf("windowed: got %v, want %v", got, w)
+	}
+	if got, w := ws.NumRatings(), wantWindowed.NumRatings(); got != w {
+		t.Errorf("windowed: got %d ratings, want %d", got, w)
+	}
+}